@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"valhalla/migrations"
 
@@ -11,10 +13,12 @@ import (
 	"valhalla/internal/application"
 	"valhalla/internal/delivery/discord"
 	"valhalla/internal/delivery/telegram"
+	"valhalla/internal/games"
 	"valhalla/internal/repository"
 	"valhalla/pkg/config"
 	"valhalla/pkg/logger"
 	"valhalla/pkg/sheets"
+	"valhalla/pkg/storage"
 
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
@@ -46,11 +50,12 @@ func main() {
 
 	repos := repository.NewRepository(&cfg.Repo, db)
 
-	gemini, err := ai.NewGeminiClient(cfg.GeminiKey)
+	aiProvider, err := newAIProvider(&cfg)
 	if err != nil {
-		log.Error("failed to init gemini: %s", err.Error())
+		log.Error("failed to init ai provider %q: %s", cfg.AIProvider, err.Error())
 		return
 	}
+	log.Info("Scoreboard provider: %s", cfg.AIProvider)
 
 	var sheetsClient sheets.Client
 	if _, err := os.Stat("google-credentials.json"); err == nil {
@@ -64,7 +69,52 @@ func main() {
 		log.Warn("google-credentials.json not found, sheets integration disabled")
 	}
 
-	services := application.NewService(repos, gemini, sheetsClient, cfg.GoogleOwnerEmail, log)
+	var blobStore storage.BlobStore
+	if cfg.StorageDir != "" {
+		localStore, err := storage.NewLocalStore(cfg.StorageDir)
+		if err != nil {
+			log.Error("failed to init screenshot storage: %s", err.Error())
+		} else {
+			blobStore = localStore
+			log.Info("Screenshots are archived to %s", cfg.StorageDir)
+		}
+	} else {
+		log.Warn("STORAGE_DIR not set, screenshots are not archived")
+	}
+
+	defaultGame, ok := games.Get(cfg.DefaultGame)
+	if !ok {
+		log.Error("unknown DEFAULT_GAME %q, expected one of: %s", cfg.DefaultGame, strings.Join(games.IDs(), ", "))
+		return
+	}
+
+	policy := application.ReviewPolicy{AutoApproveConfidence: cfg.AutoApproveConfidence}
+	if !application.IsLeaderboardSort(cfg.LeaderboardSheetSort) {
+		log.Error("unknown LEADERBOARD_SHEET_SORT %q, expected one of: %s", cfg.LeaderboardSheetSort, strings.Join(application.LeaderboardSorts, ", "))
+		return
+	}
+	leaderboard := application.LeaderboardConfig{SheetSort: cfg.LeaderboardSheetSort, MinGames: cfg.LeaderboardMinGames}
+	ingestion := application.IngestionConfig{
+		Workers:     cfg.IngestionWorkers,
+		MaxAttempts: cfg.IngestionMaxAttempts,
+		RetryDelay:  cfg.IngestionRetryDelay,
+	}
+	budget := application.AIBudget{
+		DailyCalls:        cfg.AIDailyCalls,
+		MonthlyCalls:      cfg.AIMonthlyCalls,
+		DailyTokens:       cfg.AIDailyTokens,
+		MonthlyTokens:     cfg.AIMonthlyTokens,
+		GuildDailyCalls:   cfg.AIGuildDailyCalls,
+		GuildMonthlyCalls: cfg.AIGuildMonthlyCalls,
+		UserDailyCalls:    cfg.AIUserDailyCalls,
+		UserMonthlyCalls:  cfg.AIUserMonthlyCalls,
+	}
+	services := application.NewService(repos, aiProvider, budget, sheetsClient, blobStore, policy, leaderboard, defaultGame, ingestion, cfg.GoogleOwnerEmail, log)
+
+	// Standings and ratings are derived from the approved matches, rebuild them so changes made outside the bot count
+	if _, err := services.MatchService.RebuildStats(); err != nil {
+		log.Error("failed to rebuild stats: %s", err.Error())
+	}
 
 	discordBot := discord.NewBot(&cfg, services, log)
 
@@ -82,9 +132,11 @@ func main() {
 		}
 	}()
 
+	go services.IngestionService.Run(ctx)
+
 	var telegramBot *telegram.Bot
 	if cfg.TelegramToken != "" {
-		telegramBot, err = telegram.NewBot(cfg.TelegramToken, cfg.TelegramAdminIDs, services.TelegramService, services.ProfileLinkService, log)
+		telegramBot, err = telegram.NewBot(cfg.TelegramToken, cfg.TelegramAdminIDs, defaultGame.Roles, services.TelegramService, services.ProfileLinkService, log)
 		if err != nil {
 			log.Error("failed to init telegram bot: %s", err.Error())
 		} else {
@@ -105,3 +157,16 @@ func main() {
 	}
 	log.Info("Bots Stopped")
 }
+
+func newAIProvider(cfg *config.Config) (application.AIProvider, error) {
+	switch cfg.AIProvider {
+	case "gemini":
+		return ai.NewGeminiClient(cfg.GeminiKey)
+	case "fixture":
+		return ai.NewFixtureProvider(cfg.FixturesDir)
+	case "ocr":
+		return ai.NewOCRProvider(cfg.TesseractPath, cfg.OCRLang)
+	default:
+		return nil, fmt.Errorf("unknown provider, expected gemini, fixture or ocr")
+	}
+}
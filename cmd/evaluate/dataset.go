@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"valhalla/internal/ai"
+	"valhalla/internal/models"
+)
+
+var imageExtensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true}
+
+type sample struct {
+	Name  string
+	Image []byte
+	Label *models.Match
+}
+
+// variant is one prompt/preprocessing setup to compare, unset fields keep the production values
+type variant struct {
+	Name        string   `json:"name"`
+	PromptFile  string   `json:"prompt_file"`
+	Temperature *float32 `json:"temperature"`
+	MaxWidth    int      `json:"max_width"`
+	JPEGQuality int      `json:"jpeg_quality"`
+}
+
+func (v variant) geminiOptions() (ai.GeminiOptions, error) {
+	opts := ai.GeminiOptions{
+		Temperature: v.Temperature,
+		MaxWidth:    v.MaxWidth,
+		JPEGQuality: v.JPEGQuality,
+	}
+	if v.PromptFile != "" {
+		prompt, err := os.ReadFile(v.PromptFile)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read prompt: %w", err)
+		}
+		opts.Prompt = string(prompt)
+	}
+	return opts, nil
+}
+
+func loadDataset(dir string) ([]sample, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []sample
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || !imageExtensions[ext] {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+
+		image, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		rawLabel, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "skipping %s: no label\n", e.Name())
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		label, err := ai.ParseScoreboardJSON(rawLabel)
+		if err != nil {
+			return nil, fmt.Errorf("label %s: %w", name, err)
+		}
+
+		samples = append(samples, sample{Name: name, Image: image, Label: label})
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Name < samples[j].Name })
+	return samples, nil
+}
+
+func loadVariants(path string) ([]variant, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var variants []variant
+	if err := json.Unmarshal(raw, &variants); err != nil {
+		return nil, err
+	}
+	for i, v := range variants {
+		if v.Name == "" {
+			return nil, fmt.Errorf("variant %d has no name", i+1)
+		}
+	}
+	return variants, nil
+}
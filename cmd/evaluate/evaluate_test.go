@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"valhalla/internal/ai"
+	"valhalla/internal/games"
+)
+
+// The recorded answer of mlbb_defeat misreads one name and one death count, everything else matches the labels
+func TestEvaluateFixtures(t *testing.T) {
+	samples, err := loadDataset("../../testdata/golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("got %d labeled samples, want 2", len(samples))
+	}
+	provider, err := ai.NewFixtureProvider("../../testdata/fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := evaluate("fixture", provider, games.MLBB, samples)
+
+	if r.Images != 2 || r.Failures != 0 || r.Extra != 0 {
+		t.Fatalf("images %d, failures %d, extra %d: %v", r.Images, r.Failures, r.Extra, r.Errors)
+	}
+	scores := []struct {
+		field   string
+		got     fieldScore
+		correct int
+	}{
+		{"rows", r.Rows, 20},
+		{"names", r.Names, 19},
+		{"results", r.Results, 20},
+		{"kills", r.Kills, 20},
+		{"deaths", r.Deaths, 19},
+		{"assists", r.Assists, 20},
+		{"K/D/A", r.KDA, 19},
+		{"heroes", r.Heroes, 20},
+	}
+	for _, s := range scores {
+		if s.got.Correct != s.correct || s.got.Total != 20 {
+			t.Errorf("%s = %d/%d, want %d/20", s.field, s.got.Correct, s.got.Total, s.correct)
+		}
+	}
+
+	var out bytes.Buffer
+	printReports(&out, []*report{r})
+	if !strings.Contains(out.String(), "95.0%") || !strings.Contains(out.String(), "100.0%") {
+		t.Errorf("report does not show the scores:\n%s", out.String())
+	}
+}
+
+func TestEvaluateFailedParse(t *testing.T) {
+	samples, err := loadDataset("../../testdata/golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := ai.NewFixtureProvider("../../testdata/fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := samples[0]
+	unknown.Name, unknown.Image = "unrecorded", []byte("no fixture for these bytes")
+	r := evaluate("fixture", provider, games.MLBB, []sample{unknown})
+
+	if r.Failures != 1 || len(r.Errors) != 1 {
+		t.Fatalf("failures %d, errors %v", r.Failures, r.Errors)
+	}
+	if r.Rows.Correct != 0 || r.Rows.Total != 10 || r.Heroes.Total != 10 {
+		t.Errorf("a failed parse must count every labeled row as missed, got rows %d/%d, heroes %d/%d",
+			r.Rows.Correct, r.Rows.Total, r.Heroes.Correct, r.Heroes.Total)
+	}
+}
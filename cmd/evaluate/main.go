@@ -0,0 +1,142 @@
+// Command evaluate runs a labeled screenshot dataset through a scoreboard provider and reports
+// per-field recognition accuracy for each prompt/preprocessing variant.
+//
+// Dataset layout: every image (<name>.png/.jpg/.jpeg) has a label <name>.json in the same format
+// the model answers with ({"match": {...}, "players": [...]}). testdata/golden holds a small sample set with
+// recorded answers in testdata/fixtures:
+//
+//	go run ./cmd/evaluate -provider fixture
+//
+//	go run ./cmd/evaluate -dataset testdata/golden -variants variants.json -record testdata/recorded
+//	go run ./cmd/evaluate -dataset testdata/golden -variants variants.json -replay testdata/recorded
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"valhalla/internal/ai"
+	"valhalla/internal/application"
+	"valhalla/internal/games"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	datasetDir := flag.String("dataset", "testdata/golden", "directory with screenshots and <name>.json labels")
+	variantsFile := flag.String("variants", "", "JSON file with prompt/preprocessing variants (default: production settings)")
+	providerName := flag.String("provider", "gemini", "scoreboard provider: gemini, ocr or fixture")
+	fixturesDir := flag.String("fixtures", "testdata/fixtures", "fixtures dir for -provider fixture")
+	recordDir := flag.String("record", "", "save raw gemini answers to <dir>/<variant>/<image sha256>.json")
+	replayDir := flag.String("replay", "", "answer from answers recorded with -record instead of calling gemini")
+	gameID := flag.String("game", games.DefaultID, "game profile of the dataset: "+strings.Join(games.IDs(), ", "))
+	flag.Parse()
+
+	game, ok := games.Get(*gameID)
+	if !ok {
+		fail("unknown game %q, expected %s", *gameID, strings.Join(games.IDs(), ", "))
+	}
+
+	_ = godotenv.Load()
+
+	samples, err := loadDataset(*datasetDir)
+	if err != nil {
+		fail("failed to load dataset: %v", err)
+	}
+	if len(samples) == 0 {
+		fail("no labeled screenshots in %s", *datasetDir)
+	}
+
+	variants := []variant{{Name: "baseline"}}
+	if *variantsFile != "" {
+		if variants, err = loadVariants(*variantsFile); err != nil {
+			fail("failed to load variants: %v", err)
+		}
+	}
+
+	var reports []*report
+	switch *providerName {
+	case "gemini":
+		for _, v := range variants {
+			provider, err := newVariantProvider(v, *recordDir, *replayDir)
+			if err != nil {
+				fail("variant %s: %v", v.Name, err)
+			}
+			reports = append(reports, evaluate(v.Name, provider, game, samples))
+		}
+	case "ocr":
+		provider, err := ai.NewOCRProvider(envOrDefault("TESSERACT_PATH", "tesseract"), envOrDefault("OCR_LANG", "eng"))
+		if err != nil {
+			fail("%v", err)
+		}
+		reports = append(reports, evaluate("ocr", provider, game, samples))
+	case "fixture":
+		provider, err := ai.NewFixtureProvider(*fixturesDir)
+		if err != nil {
+			fail("%v", err)
+		}
+		reports = append(reports, evaluate("fixture", provider, game, samples))
+	default:
+		fail("unknown provider %q, expected gemini, ocr or fixture", *providerName)
+	}
+
+	printReports(os.Stdout, reports)
+}
+
+func newVariantProvider(v variant, recordDir, replayDir string) (application.AIProvider, error) {
+	if replayDir != "" {
+		return ai.NewFixtureProvider(filepath.Join(replayDir, v.Name))
+	}
+
+	opts, err := v.geminiOptions()
+	if err != nil {
+		return nil, err
+	}
+	if recordDir != "" {
+		dir := filepath.Join(recordDir, v.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create record dir: %w", err)
+		}
+		opts.OnResponse = func(image, raw []byte) {
+			if err := os.WriteFile(ai.FixturePath(dir, image), raw, 0o644); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to record answer: %v\n", err)
+			}
+		}
+	}
+	return ai.NewGeminiClientWithOptions(os.Getenv("GEMINI_KEY"), opts)
+}
+
+func evaluate(name string, provider application.AIProvider, game *games.Profile, samples []sample) *report {
+	r := &report{Variant: name}
+	for _, s := range samples {
+		start := time.Now()
+		parsed, usage, err := provider.ParseImage(s.Image, game)
+		r.Latency += time.Since(start)
+		r.Tokens += usage.TotalTokens
+		r.Images++
+
+		if err != nil {
+			r.Failures++
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", s.Name, err))
+			r.scoreMissing(s.Label)
+			continue
+		}
+		r.score(s.Label, parsed)
+	}
+	return r
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"valhalla/internal/ai"
+	"valhalla/internal/models"
+)
+
+// minPairSimilarity is how close a parsed name must be to a labeled one to be scored as the same row
+const minPairSimilarity = 0.5
+
+type fieldScore struct {
+	Correct int
+	Total   int
+}
+
+func (f *fieldScore) add(ok bool) {
+	f.Total++
+	if ok {
+		f.Correct++
+	}
+}
+
+func (f fieldScore) String() string {
+	if f.Total == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%.1f%%", float64(f.Correct)/float64(f.Total)*100)
+}
+
+type report struct {
+	Variant  string
+	Images   int
+	Failures int
+	Latency  time.Duration
+	Tokens   int
+	Errors   []string
+
+	Rows    fieldScore // labeled players found in the parse at all
+	Extra   int        // parsed players with no labeled counterpart
+	Names   fieldScore
+	Results fieldScore
+	Kills   fieldScore
+	Deaths  fieldScore
+	Assists fieldScore
+	KDA     fieldScore // all three numbers right
+	Heroes  fieldScore
+}
+
+// score pairs every labeled player with the most similar unused parsed name and compares the fields
+func (r *report) score(label, parsed *models.Match) {
+	used := make([]bool, len(parsed.Players))
+	for _, want := range label.Players {
+		best, bestScore := -1, minPairSimilarity
+		for j, got := range parsed.Players {
+			if used[j] {
+				continue
+			}
+			if score := ai.SimilarityScore(want.PlayerName, got.PlayerName); score >= bestScore {
+				best, bestScore = j, score
+			}
+		}
+		if best < 0 {
+			r.scoreMissingPlayer(want)
+			continue
+		}
+		used[best] = true
+		got := parsed.Players[best]
+
+		r.Rows.add(true)
+		r.Names.add(strings.TrimSpace(want.PlayerName) == strings.TrimSpace(got.PlayerName))
+		r.Results.add(want.Result == got.Result)
+		r.Kills.add(want.Kills == got.Kills)
+		r.Deaths.add(want.Deaths == got.Deaths)
+		r.Assists.add(want.Assists == got.Assists)
+		r.KDA.add(want.Kills == got.Kills && want.Deaths == got.Deaths && want.Assists == got.Assists)
+		if want.Champion != "" {
+			r.Heroes.add(strings.EqualFold(want.Champion, got.Champion))
+		}
+	}
+	for _, u := range used {
+		if !u {
+			r.Extra++
+		}
+	}
+}
+
+func (r *report) scoreMissing(label *models.Match) {
+	for _, want := range label.Players {
+		r.scoreMissingPlayer(want)
+	}
+}
+
+func (r *report) scoreMissingPlayer(want models.PlayerResult) {
+	for _, f := range []*fieldScore{&r.Rows, &r.Names, &r.Results, &r.Kills, &r.Deaths, &r.Assists, &r.KDA} {
+		f.add(false)
+	}
+	if want.Champion != "" {
+		r.Heroes.add(false)
+	}
+}
+
+func printReports(w io.Writer, reports []*report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "variant\timages\tfailed\trows\textra\tnames\tresult\tkills\tdeaths\tassists\tK/D/A\theroes\tavg latency\tavg tokens\t")
+	for _, r := range reports {
+		avg, avgTokens := time.Duration(0), 0
+		if r.Images > 0 {
+			avg = r.Latency / time.Duration(r.Images)
+			avgTokens = r.Tokens / r.Images
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t\n",
+			r.Variant, r.Images, r.Failures, r.Rows, r.Extra, r.Names, r.Results,
+			r.Kills, r.Deaths, r.Assists, r.KDA, r.Heroes, avg.Round(time.Millisecond), avgTokens)
+	}
+	tw.Flush()
+
+	for _, r := range reports {
+		if len(r.Errors) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s failures:\n", r.Variant)
+		for _, e := range r.Errors {
+			fmt.Fprintf(w, "  %s\n", e)
+		}
+	}
+}
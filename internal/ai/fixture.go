@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// FixtureProvider answers with canned model responses stored as <dir>/<sha256 of image>.json,
+// so the ingestion path can run without network access or an API key
+type FixtureProvider struct {
+	dir string
+}
+
+func NewFixtureProvider(dir string) (*FixtureProvider, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("fixtures dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixtures dir %s is not a directory", dir)
+	}
+	return &FixtureProvider{dir: dir}, nil
+}
+
+// ParseImage reports no token usage, recorded answers do not keep it
+func (f *FixtureProvider) ParseImage(data []byte, _ *games.Profile) (*models.Match, models.TokenUsage, error) {
+	raw, err := os.ReadFile(FixturePath(f.dir, data))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, models.TokenUsage{}, fmt.Errorf("no fixture for image %s", ImageHash(data))
+	}
+	if err != nil {
+		return nil, models.TokenUsage{}, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	match, err := ParseScoreboardJSON(raw)
+	return match, models.TokenUsage{}, err
+}
+
+// ReparseImage returns the same canned answer, a fixture cannot correct itself
+func (f *FixtureProvider) ReparseImage(data []byte, game *games.Profile, _ []string) (*models.Match, models.TokenUsage, error) {
+	return f.ParseImage(data, game)
+}
+
+// FixturePath is where the canned response for an image is stored
+func FixturePath(dir string, data []byte) string {
+	return filepath.Join(dir, ImageHash(data)+fixtureExt)
+}
+
+// ImageHash is the SHA-256 hex digest of the raw image bytes, the same key used for file hash dedup
+func ImageHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
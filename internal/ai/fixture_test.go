@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"valhalla/internal/games"
+)
+
+// fixturesDir is the recorded answer set used by AI_PROVIDER=fixture, keyed by the screenshots in goldenDir
+const (
+	fixturesDir = "../../testdata/fixtures"
+	goldenDir   = "../../testdata/golden"
+)
+
+func TestFixtureProvider(t *testing.T) {
+	provider, err := NewFixtureProvider(fixturesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		image      string
+		wantWins   int
+		wantLosses int
+		firstName  string
+	}{
+		{image: "mlbb_victory.png", wantWins: 5, wantLosses: 5, firstName: "Shadow"},
+		{image: "mlbb_defeat.png", wantWins: 5, wantLosses: 5, firstName: "Shadow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(goldenDir, tt.image))
+			if err != nil {
+				t.Fatal(err)
+			}
+			match, usage, err := provider.ParseImage(data, games.MLBB)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if usage.TotalTokens != 0 {
+				t.Errorf("fixtures report no usage, got %d tokens", usage.TotalTokens)
+			}
+
+			wins, losses := 0, 0
+			for _, p := range match.Players {
+				switch p.Result {
+				case "WIN":
+					wins++
+				case "LOSE":
+					losses++
+				}
+			}
+			if wins != tt.wantWins || losses != tt.wantLosses {
+				t.Errorf("got %d WIN and %d LOSE, want %d and %d", wins, losses, tt.wantWins, tt.wantLosses)
+			}
+			if match.Players[0].PlayerName != tt.firstName {
+				t.Errorf("first player = %q, want %q", match.Players[0].PlayerName, tt.firstName)
+			}
+		})
+	}
+
+	t.Run("unknown image", func(t *testing.T) {
+		if _, _, err := provider.ParseImage([]byte("not recorded"), games.MLBB); err == nil {
+			t.Fatal("expected an error for an image without a fixture")
+		}
+	})
+}
@@ -2,8 +2,9 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
+	"valhalla/internal/games"
 	"valhalla/internal/models"
 
 	"github.com/google/generative-ai-go/genai"
@@ -11,27 +12,76 @@ import (
 )
 
 type GeminiClient struct {
-	model *genai.GenerativeModel
+	model     *genai.GenerativeModel
+	prompt    string
+	processor *ImageProcessor
+	onRaw     func(image, raw []byte)
+}
+
+// GeminiOptions tunes the prompt and preprocessing, zero values keep the production defaults
+type GeminiOptions struct {
+	// Prompt replaces the game profile prompt for every game
+	Prompt      string
+	Temperature *float32
+	MaxWidth    int
+	JPEGQuality int
+
+	// OnResponse receives the original image and the raw model answer of every call, used to record fixtures
+	OnResponse func(image, raw []byte)
 }
 
 func NewGeminiClient(apiKey string) (*GeminiClient, error) {
+	return NewGeminiClientWithOptions(apiKey, GeminiOptions{})
+}
+
+func NewGeminiClientWithOptions(apiKey string, opts GeminiOptions) (*GeminiClient, error) {
 	ctx := context.Background()
 	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
 	if err != nil {
 		return nil, err
 	}
 
+	temperature := float32(aiTemperature)
+	if opts.Temperature != nil {
+		temperature = *opts.Temperature
+	}
+
 	model := client.GenerativeModel(geminiModel)
 	model.ResponseMIMEType = responseMIMEType
-	model.SetTemperature(aiTemperature)
+	model.SetTemperature(temperature)
+
+	return &GeminiClient{
+		model:     model,
+		prompt:    opts.Prompt,
+		processor: NewImageProcessorWithOptions(opts.MaxWidth, opts.JPEGQuality),
+		onRaw:     opts.OnResponse,
+	}, nil
+}
+
+func (g *GeminiClient) ParseImage(data []byte, game *games.Profile) (*models.Match, models.TokenUsage, error) {
+	return g.parse(data, g.promptFor(game))
+}
+
+// ReparseImage parses the image again, telling the model which rules its previous answer broke
+func (g *GeminiClient) ReparseImage(data []byte, game *games.Profile, violations []string) (*models.Match, models.TokenUsage, error) {
+	var sb strings.Builder
+	for _, v := range violations {
+		sb.WriteString("    - " + v + "\n")
+	}
+	return g.parse(data, g.promptFor(game)+fmt.Sprintf(CorrectionPromptTemplate, sb.String()))
+}
 
-	return &GeminiClient{model: model}, nil
+func (g *GeminiClient) promptFor(game *games.Profile) string {
+	if g.prompt != "" {
+		return g.prompt
+	}
+	return game.Prompt
 }
 
-func (g *GeminiClient) ParseImage(data []byte) (*models.Match, error) {
+// parse returns the token usage even when the answer could not be read, the tokens are spent either way
+func (g *GeminiClient) parse(data []byte, promptText string) (*models.Match, models.TokenUsage, error) {
 	// Optimize image before sending to API (compress + resize)
-	processor := NewImageProcessor()
-	optimizedData, err := processor.OptimizeForAI(data)
+	optimizedData, err := g.processor.OptimizeForAI(data)
 	if err != nil {
 		// Fallback to original if optimization fails
 		optimizedData = data
@@ -39,29 +89,39 @@ func (g *GeminiClient) ParseImage(data []byte) (*models.Match, error) {
 
 	prompt := []genai.Part{
 		genai.ImageData("jpeg", optimizedData), // Use optimized JPEG
-		genai.Text(ParseImagePrompt),
+		genai.Text(promptText),
 	}
 
 	resp, err := g.model.GenerateContent(context.Background(), prompt...)
 	if err != nil {
-		return nil, err
+		return nil, models.TokenUsage{}, err
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("empty response from AI")
+	usage := tokenUsage(resp.UsageMetadata)
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, usage, fmt.Errorf("empty response from AI")
 	}
 
 	rawText, ok := resp.Candidates[0].Content.Parts[0].(genai.Text)
 	if !ok {
-		return nil, fmt.Errorf("unexpected response format")
+		return nil, usage, fmt.Errorf("unexpected response format")
 	}
 
-	var results []models.PlayerResult
-	if err := json.Unmarshal([]byte(rawText), &results); err != nil {
-		return nil, fmt.Errorf("json unmarshal error: %w | raw: %s", err, rawText)
+	if g.onRaw != nil {
+		g.onRaw(data, []byte(rawText))
 	}
 
-	return &models.Match{
-		Players: results,
-	}, nil
+	match, err := ParseScoreboardJSON([]byte(rawText))
+	return match, usage, err
+}
+
+func tokenUsage(meta *genai.UsageMetadata) models.TokenUsage {
+	if meta == nil {
+		return models.TokenUsage{}
+	}
+	return models.TokenUsage{
+		PromptTokens:   int(meta.PromptTokenCount),
+		ResponseTokens: int(meta.CandidatesTokenCount),
+		TotalTokens:    int(meta.TotalTokenCount),
+	}
 }
@@ -5,20 +5,63 @@ import (
 	"fmt"
 	"image"
 	"image/jpeg"
-	_ "image/png" // Register PNG decoder
+	"image/png"
 
 	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/webp"
 )
 
 const (
 	maxImageWidth = 1000
 	jpegQuality   = 75
+
+	// Tesseract reads small UI digits poorly, narrower screenshots are upscaled first
+	minOCRWidth = 1600
+	ocrContrast = 30
+
+	// dHash grid: 9x8 pixels give 8 horizontal gradients per row, 64 bits total
+	dHashWidth  = 9
+	dHashHeight = 8
 )
 
-type ImageProcessor struct{}
+type ImageProcessor struct {
+	maxWidth    int
+	jpegQuality int
+}
 
 func NewImageProcessor() *ImageProcessor {
-	return &ImageProcessor{}
+	return NewImageProcessorWithOptions(maxImageWidth, jpegQuality)
+}
+
+// NewImageProcessorWithOptions overrides the AI preprocessing parameters, non-positive values keep the defaults
+func NewImageProcessorWithOptions(maxWidth, quality int) *ImageProcessor {
+	if maxWidth <= 0 {
+		maxWidth = maxImageWidth
+	}
+	if quality <= 0 {
+		quality = jpegQuality
+	}
+	return &ImageProcessor{maxWidth: maxWidth, jpegQuality: quality}
+}
+
+// PrepareForOCR converts the image to an upscaled, contrast-boosted grayscale PNG for tesseract
+func (p *ImageProcessor) PrepareForOCR(data []byte) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	prepared := imaging.Grayscale(img)
+	if prepared.Bounds().Dx() < minOCRWidth {
+		prepared = imaging.Resize(prepared, minOCRWidth, 0, imaging.Lanczos)
+	}
+	prepared = imaging.AdjustContrast(prepared, ocrContrast)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, prepared); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
 }
 
 func (p *ImageProcessor) OptimizeForAI(data []byte) ([]byte, error) {
@@ -34,12 +77,12 @@ func (p *ImageProcessor) OptimizeForAI(data []byte) ([]byte, error) {
 
 	var optimized image.Image = img
 
-	if width > maxImageWidth {
-		optimized = imaging.Resize(img, maxImageWidth, 0, imaging.Lanczos)
+	if width > p.maxWidth {
+		optimized = imaging.Resize(img, p.maxWidth, 0, imaging.Lanczos)
 	}
 
 	var buf bytes.Buffer
-	err = jpeg.Encode(&buf, optimized, &jpeg.Options{Quality: jpegQuality})
+	err = jpeg.Encode(&buf, optimized, &jpeg.Options{Quality: p.jpegQuality})
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode jpeg: %w", err)
 	}
@@ -54,3 +97,28 @@ func (p *ImageProcessor) OptimizeForAI(data []byte) ([]byte, error) {
 
 	return compressed, nil
 }
+
+// PerceptualHash computes a 64-bit difference hash (dHash) of the image. Unlike a file hash it survives
+// re-compression, resizing and small crops, so two screenshots of the same scoreboard have close hashes.
+func (p *ImageProcessor) PerceptualHash(data []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	small := imaging.Grayscale(imaging.Resize(img, dHashWidth, dHashHeight, imaging.Box))
+
+	var hash uint64
+	for y := 0; y < dHashHeight; y++ {
+		for x := 0; x < dHashWidth-1; x++ {
+			left := small.Pix[small.PixOffset(x, y)]
+			right := small.Pix[small.PixOffset(x+1, y)]
+			hash <<= 1
+			if left > right {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, nil
+}
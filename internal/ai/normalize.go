@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+	"valhalla/internal/models"
 )
 
 var nonAlphaNumericRegex = regexp.MustCompile(`[^\p{L}\p{N}\s._-]`)
@@ -32,6 +33,49 @@ func NormalizeName(name string) string {
 	return strings.TrimSpace(result.String())
 }
 
+// NormalizeResult maps result labels like "Win", "Victory" or "Defeat" to WIN / LOSE
+func NormalizeResult(result string) string {
+	result = strings.ToUpper(strings.TrimSpace(result))
+	switch result {
+	case "WIN", "VICTORY", "WON":
+		return "WIN"
+	case "LOSE", "LOSS", "DEFEAT", "LOST":
+		return "LOSE"
+	default:
+		return result
+	}
+}
+
+// NormalizeTeam maps the side label returned by the model to models.TeamBlue / models.TeamRed
+func NormalizeTeam(team string) string {
+	team = strings.ToLower(strings.TrimSpace(team))
+	switch {
+	case strings.Contains(team, "blue"), team == "left":
+		return models.TeamBlue
+	case strings.Contains(team, "red"), team == "right":
+		return models.TeamRed
+	default:
+		return ""
+	}
+}
+
+// NormalizeMedal maps the medal label returned by the model to one of the models.Medal* values
+func NormalizeMedal(medal string) string {
+	medal = strings.ToUpper(strings.TrimSpace(medal))
+	switch {
+	case strings.Contains(medal, "MVP"):
+		return models.MedalMVP
+	case strings.Contains(medal, "GOLD"):
+		return models.MedalGold
+	case strings.Contains(medal, "SILVER"):
+		return models.MedalSilver
+	case strings.Contains(medal, "BRONZE"):
+		return models.MedalBronze
+	default:
+		return ""
+	}
+}
+
 func SimilarityScore(a, b string) float64 {
 	if a == b {
 		return 1.0
@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+var (
+	kdaPattern = regexp.MustCompile(`(\d{1,2})\s*/\s*(\d{1,2})\s*/\s*(\d{1,2})`)
+
+	// shortNumberPattern matches shortened gold and damage values such as "12.3k"
+	shortNumberPattern = regexp.MustCompile(`^\d+([.,]\d+)?[kKmMкК]$`)
+)
+
+// resultMarkers are the banner words telling whether the uploader's team won
+var resultMarkers = map[string]string{
+	"VICTORY":   "WIN",
+	"ПОБЕДА":    "WIN",
+	"DEFEAT":    "LOSE",
+	"ПОРАЖЕНИЕ": "LOSE",
+}
+
+// OCRProvider reads scoreboards with a local tesseract binary and needs no network. It recovers only names,
+// K/D/A and the result, heroes and detailed stats are left empty
+type OCRProvider struct {
+	binary string
+	lang   string
+}
+
+func NewOCRProvider(binary, lang string) (*OCRProvider, error) {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("tesseract not found: %w", err)
+	}
+	return &OCRProvider{binary: path, lang: lang}, nil
+}
+
+// ParseImage runs locally, the token usage is always zero
+func (o *OCRProvider) ParseImage(data []byte, _ *games.Profile) (*models.Match, models.TokenUsage, error) {
+	text, err := o.recognize(data, ocrPageSegBlock)
+	if err != nil {
+		return nil, models.TokenUsage{}, err
+	}
+	match, err := ParseScoreboardText(text)
+	return match, models.TokenUsage{}, err
+}
+
+// ReparseImage retries with sparse text segmentation, which copes better with overlays between rows
+func (o *OCRProvider) ReparseImage(data []byte, _ *games.Profile, _ []string) (*models.Match, models.TokenUsage, error) {
+	text, err := o.recognize(data, ocrPageSegSparse)
+	if err != nil {
+		return nil, models.TokenUsage{}, err
+	}
+	match, err := ParseScoreboardText(text)
+	return match, models.TokenUsage{}, err
+}
+
+func (o *OCRProvider) recognize(data []byte, pageSegMode string) (string, error) {
+	prepared, err := NewImageProcessor().PrepareForOCR(data)
+	if err != nil {
+		// Fallback to original if preprocessing fails
+		prepared = data
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ocrTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, o.binary, "stdin", "stdout", "-l", o.lang, "--psm", pageSegMode)
+	cmd.Stdin = bytes.NewReader(prepared)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// ParseScoreboardText extracts players from OCR text. Every "K/D/A" group is a player row and the words before
+// it are the name. Scoreboards with both teams side by side put two groups on a line, left column first;
+// stacked scoreboards list the uploader's team in the first half. The result banner decides who won
+func ParseScoreboardText(text string) (*models.Match, error) {
+	var left, right []models.PlayerResult
+	firstResult := ""
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if firstResult == "" {
+			firstResult = detectResult(line)
+		}
+
+		start := 0
+		groups := kdaPattern.FindAllStringSubmatchIndex(line, -1)
+		for idx, loc := range groups {
+			name := cleanOCRName(line[start:loc[0]])
+			start = loc[1]
+			if name == "" {
+				continue
+			}
+
+			kills, _ := strconv.Atoi(line[loc[2]:loc[3]])
+			deaths, _ := strconv.Atoi(line[loc[4]:loc[5]])
+			assists, _ := strconv.Atoi(line[loc[6]:loc[7]])
+			p := models.PlayerResult{PlayerName: name, Kills: kills, Deaths: deaths, Assists: assists}
+			if idx == 0 {
+				left = append(left, p)
+			} else {
+				right = append(right, p)
+			}
+		}
+	}
+
+	if firstResult == "" {
+		return nil, fmt.Errorf("не удалось определить исход матча (VICTORY/DEFEAT)")
+	}
+	secondResult := "LOSE"
+	if firstResult == "LOSE" {
+		secondResult = "WIN"
+	}
+
+	if len(right) == 0 {
+		half := len(left) / 2
+		left, right = left[:half], left[half:]
+	}
+
+	players := make([]models.PlayerResult, 0, len(left)+len(right))
+	for _, p := range left {
+		p.Result = firstResult
+		players = append(players, p)
+	}
+	for _, p := range right {
+		p.Result = secondResult
+		players = append(players, p)
+	}
+	return &models.Match{Players: players}, nil
+}
+
+func detectResult(line string) string {
+	upper := strings.ToUpper(line)
+	for marker, result := range resultMarkers {
+		if strings.Contains(upper, marker) {
+			return result
+		}
+	}
+	return ""
+}
+
+// cleanOCRName drops tokens without letters (gold, scores, rank digits), shortened numbers and stray punctuation
+// around the name
+func cleanOCRName(s string) string {
+	var words []string
+	for _, word := range strings.Fields(s) {
+		word = strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if strings.IndexFunc(word, unicode.IsLetter) >= 0 && !shortNumberPattern.MatchString(word) {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
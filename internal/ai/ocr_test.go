@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+	"valhalla/internal/models"
+)
+
+func TestParseScoreboardText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []models.PlayerResult
+		wantErr bool
+	}{
+		{
+			name: "teams side by side",
+			text: "VICTORY 21 - 9\n" +
+				"1 Shadow 5/1/7  12.3k   Vortex 2/4/1 8.1k\n" +
+				"2 [VH] Luna 3 / 2 / 10   Ёжик 1/3/2\n",
+			want: []models.PlayerResult{
+				{PlayerName: "Shadow", Result: "WIN", Kills: 5, Deaths: 1, Assists: 7},
+				{PlayerName: "VH Luna", Result: "WIN", Kills: 3, Deaths: 2, Assists: 10},
+				{PlayerName: "Vortex", Result: "LOSE", Kills: 2, Deaths: 4, Assists: 1},
+				{PlayerName: "Ёжик", Result: "LOSE", Kills: 1, Deaths: 3, Assists: 2},
+			},
+		},
+		{
+			name: "stacked teams",
+			text: "ПОРАЖЕНИЕ\nShadow 0/5/2\nLuna 1/4/3\nVortex 6/0/4\nPixel 3/1/8\n",
+			want: []models.PlayerResult{
+				{PlayerName: "Shadow", Result: "LOSE", Kills: 0, Deaths: 5, Assists: 2},
+				{PlayerName: "Luna", Result: "LOSE", Kills: 1, Deaths: 4, Assists: 3},
+				{PlayerName: "Vortex", Result: "WIN", Kills: 6, Deaths: 0, Assists: 4},
+				{PlayerName: "Pixel", Result: "WIN", Kills: 3, Deaths: 1, Assists: 8},
+			},
+		},
+		{
+			name: "rows without a name are skipped",
+			text: "Defeat\n12 / 3 / 4\nShadow 1/2/3\nLuna 4/5/6\n",
+			want: []models.PlayerResult{
+				{PlayerName: "Shadow", Result: "LOSE", Kills: 1, Deaths: 2, Assists: 3},
+				{PlayerName: "Luna", Result: "WIN", Kills: 4, Deaths: 5, Assists: 6},
+			},
+		},
+		{
+			name:    "no result banner",
+			text:    "Shadow 1/2/3\nLuna 4/5/6\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScoreboardText(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Players, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got.Players, tt.want)
+			}
+		})
+	}
+}
@@ -1,27 +1,23 @@
 package ai
 
+import "time"
+
 const (
 	geminiModel      = "gemini-2.5-flash"
 	aiTemperature    = 0.1
 	responseMIMEType = "application/json"
 )
 
-const ParseImagePrompt = `Analyze this MOBA (Mobile Legends) scoreboard screenshot.
-    Extract data for ALL 10 players visible in the match results.
-    
-    CRITICAL RULES FOR PLAYER NAMES:
-    - Extract player names EXACTLY as shown, character by character
-    - DO NOT add or remove any characters from the name
-    - DO NOT confuse similar characters (n vs m, l vs I, 0 vs O)
-    - If a name has special characters (icons, flags, symbols), include them only if clearly readable
-    - If a name is partially obscured, extract only the visible portion
-    - Names must be CONSISTENT - the same player should have the exact same name
-    
-    For each player extract: player_name, result (WIN or LOSE), kills, deaths, assists.
-    
-    Return a JSON array of objects with these exact keys:
-    "player_name" (string - exact name as displayed), 
-    "result" (string - must be "WIN" or "LOSE"), 
-    "kills" (int), 
-    "deaths" (int), 
-    "assists" (int).`
+const (
+	fixtureExt       = ".json"
+	ocrTimeout       = 30 * time.Second
+	ocrPageSegBlock  = "6"  // assume a single uniform block of text
+	ocrPageSegSparse = "11" // find as much text as possible in no particular order
+)
+
+// CorrectionPromptTemplate is appended to the parse prompt when the previous answer failed validation
+const CorrectionPromptTemplate = `
+
+    YOUR PREVIOUS ANSWER FOR THIS SCREENSHOT WAS REJECTED because of these problems:
+%s
+    Look at the screenshot again carefully and return a corrected JSON object that fixes ALL of them.`
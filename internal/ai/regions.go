@@ -0,0 +1,223 @@
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// Region detection runs on a downscaled grayscale copy
+	regionAnalysisWidth = 480
+
+	// A row or column whose mean gradient is below this (0-255 scale) is treated as blank background
+	blankActivity = 4.0
+
+	// Blank strips at least this share of the region size separate scoreboards in a collage
+	minGutterFraction = 0.01
+	minGutterPixels   = 3
+
+	// Regions smaller than this share of the image, or taller than wide, are overlays or UI fragments
+	minRegionArea   = 0.15
+	minRegionAspect = 1.0
+
+	// Collages are split at most this many times in depth (up to 4 scoreboards)
+	maxRegionDepth = 2
+
+	regionPadding = 4
+)
+
+// activityMap holds the gradient magnitude of the downscaled image, high where there is text or edges
+type activityMap struct {
+	width, height int
+	values        []float64
+}
+
+func (a *activityMap) at(x, y int) float64 {
+	return a.values[y*a.width+x]
+}
+
+func (a *activityMap) rowActivity(y int, r image.Rectangle) float64 {
+	sum := 0.0
+	for x := r.Min.X; x < r.Max.X; x++ {
+		sum += a.at(x, y)
+	}
+	return sum / float64(r.Dx())
+}
+
+func (a *activityMap) colActivity(x int, r image.Rectangle) float64 {
+	sum := 0.0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sum += a.at(x, y)
+	}
+	return sum / float64(r.Dy())
+}
+
+// DetectScoreboards finds scoreboard regions in a screenshot and returns each one cropped and PNG encoded.
+// Uniform margins (black bars, empty UI) are trimmed and collages are cut along blank gutters; small or
+// portrait fragments such as chat overlays are dropped. When nothing sensible is found, the whole image is returned
+func (p *ImageProcessor) DetectScoreboards(data []byte) ([][]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	analysis := imaging.Grayscale(img)
+	if bounds.Dx() > regionAnalysisWidth {
+		analysis = imaging.Resize(analysis, regionAnalysisWidth, 0, imaging.Box)
+	}
+	activity := buildActivityMap(analysis)
+	scale := float64(bounds.Dx()) / float64(activity.width)
+
+	full := image.Rect(0, 0, activity.width, activity.height)
+	rects := detectRegions(activity, full, full, 0)
+	if len(rects) == 0 {
+		rects = []image.Rectangle{full}
+	}
+
+	regions := make([][]byte, 0, len(rects))
+	for _, r := range rects {
+		crop := image.Rect(
+			int(float64(r.Min.X)*scale)-regionPadding, int(float64(r.Min.Y)*scale)-regionPadding,
+			int(float64(r.Max.X)*scale)+regionPadding, int(float64(r.Max.Y)*scale)+regionPadding,
+		).Add(bounds.Min).Intersect(bounds)
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, imaging.Crop(img, crop)); err != nil {
+			return nil, fmt.Errorf("failed to encode region: %w", err)
+		}
+		regions = append(regions, buf.Bytes())
+	}
+	return regions, nil
+}
+
+func buildActivityMap(img *image.NRGBA) *activityMap {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	a := &activityMap{width: w, height: h, values: make([]float64, w*h)}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := float64(img.Pix[img.PixOffset(x, y)])
+			grad := 0.0
+			if x > 0 {
+				grad += abs(v - float64(img.Pix[img.PixOffset(x-1, y)]))
+			}
+			if y > 0 {
+				grad += abs(v - float64(img.Pix[img.PixOffset(x, y-1)]))
+			}
+			a.values[y*w+x] = grad
+		}
+	}
+	return a
+}
+
+// detectRegions trims blank margins of r and recursively cuts it along blank gutters (XY-cut).
+// If no piece of a cut qualifies as a scoreboard, r itself is kept
+func detectRegions(a *activityMap, full, r image.Rectangle, depth int) []image.Rectangle {
+	r = trimBlank(a, r)
+	if r.Empty() {
+		return nil
+	}
+
+	if depth < maxRegionDepth {
+		parts := splitRows(a, r)
+		if len(parts) < 2 {
+			parts = splitCols(a, r)
+		}
+		if len(parts) >= 2 {
+			var found []image.Rectangle
+			for _, part := range parts {
+				found = append(found, detectRegions(a, full, part, depth+1)...)
+			}
+			if len(found) > 0 {
+				return found
+			}
+		}
+	}
+
+	if !isScoreboardShaped(full, r) {
+		return nil
+	}
+	return []image.Rectangle{r}
+}
+
+func trimBlank(a *activityMap, r image.Rectangle) image.Rectangle {
+	for r.Min.Y < r.Max.Y && a.rowActivity(r.Min.Y, r) < blankActivity {
+		r.Min.Y++
+	}
+	for r.Max.Y > r.Min.Y && a.rowActivity(r.Max.Y-1, r) < blankActivity {
+		r.Max.Y--
+	}
+	for r.Min.X < r.Max.X && a.colActivity(r.Min.X, r) < blankActivity {
+		r.Min.X++
+	}
+	for r.Max.X > r.Min.X && a.colActivity(r.Max.X-1, r) < blankActivity {
+		r.Max.X--
+	}
+	return r
+}
+
+func splitRows(a *activityMap, r image.Rectangle) []image.Rectangle {
+	blank := make([]bool, r.Dy())
+	for i := range blank {
+		blank[i] = a.rowActivity(r.Min.Y+i, r) < blankActivity
+	}
+	var parts []image.Rectangle
+	for _, span := range contentSpans(blank, gutterSize(r.Dy())) {
+		parts = append(parts, image.Rect(r.Min.X, r.Min.Y+span[0], r.Max.X, r.Min.Y+span[1]))
+	}
+	return parts
+}
+
+func splitCols(a *activityMap, r image.Rectangle) []image.Rectangle {
+	blank := make([]bool, r.Dx())
+	for i := range blank {
+		blank[i] = a.colActivity(r.Min.X+i, r) < blankActivity
+	}
+	var parts []image.Rectangle
+	for _, span := range contentSpans(blank, gutterSize(r.Dx())) {
+		parts = append(parts, image.Rect(r.Min.X+span[0], r.Min.Y, r.Min.X+span[1], r.Max.Y))
+	}
+	return parts
+}
+
+// contentSpans returns [start, end) spans separated by runs of at least minGutter blank lines
+func contentSpans(blank []bool, minGutter int) [][2]int {
+	var spans [][2]int
+	start, run := 0, 0
+	for i, b := range blank {
+		if !b {
+			if run >= minGutter && i-run > start {
+				spans = append(spans, [2]int{start, i - run})
+				start = i
+			}
+			run = 0
+			continue
+		}
+		run++
+	}
+	return append(spans, [2]int{start, len(blank)})
+}
+
+func gutterSize(size int) int {
+	g := int(float64(size) * minGutterFraction)
+	if g < minGutterPixels {
+		return minGutterPixels
+	}
+	return g
+}
+
+func isScoreboardShaped(full, r image.Rectangle) bool {
+	area := float64(r.Dx()*r.Dy()) / float64(full.Dx()*full.Dy())
+	return area >= minRegionArea && float64(r.Dx()) >= float64(r.Dy())*minRegionAspect
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"valhalla/internal/models"
+)
+
+// playedAtLayouts are the in-game timestamp formats the model is asked for or commonly returns
+var playedAtLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"02.01.2006 15:04",
+	"2006/01/02 15:04",
+	"01/02/2006 15:04",
+}
+
+type scoreboardResponse struct {
+	Match   scoreboardMeta        `json:"match"`
+	Players []models.PlayerResult `json:"players"`
+}
+
+type scoreboardMeta struct {
+	BlueScore int    `json:"blue_score"`
+	RedScore  int    `json:"red_score"`
+	Duration  string `json:"duration"`
+	GameMode  string `json:"game_mode"`
+	EndedAt   string `json:"ended_at"`
+}
+
+// ParseScoreboardJSON converts a raw model answer into a match. Both the current object format and
+// the legacy bare array of players are accepted.
+func ParseScoreboardJSON(raw []byte) (*models.Match, error) {
+	raw = bytes.TrimSpace(raw)
+
+	var resp scoreboardResponse
+	if len(raw) > 0 && raw[0] == '[' {
+		if err := json.Unmarshal(raw, &resp.Players); err != nil {
+			return nil, fmt.Errorf("json unmarshal error: %w | raw: %s", err, raw)
+		}
+	} else if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("json unmarshal error: %w | raw: %s", err, raw)
+	}
+
+	for i := range resp.Players {
+		p := &resp.Players[i]
+		p.RawName = strings.TrimSpace(p.RawName)
+		p.PlayerName = strings.TrimSpace(p.PlayerName)
+		if p.PlayerName == "" {
+			p.PlayerName = NormalizeName(p.RawName)
+		}
+		if p.RawName == "" {
+			p.RawName = p.PlayerName
+		}
+		p.NameConfidence = clampConfidence(p.NameConfidence)
+		p.StatsConfidence = clampConfidence(p.StatsConfidence)
+		p.Result = NormalizeResult(p.Result)
+		p.Team = NormalizeTeam(p.Team)
+		p.Champion = strings.TrimSpace(p.Champion)
+		p.Medal = NormalizeMedal(p.Medal)
+	}
+
+	match := &models.Match{
+		Players:     resp.Players,
+		BlueScore:   resp.Match.BlueScore,
+		RedScore:    resp.Match.RedScore,
+		DurationSec: parseDuration(resp.Match.Duration),
+		GameMode:    strings.TrimSpace(resp.Match.GameMode),
+	}
+	if playedAt, ok := parsePlayedAt(resp.Match.EndedAt); ok {
+		match.PlayedAt = &playedAt
+	}
+	return match, nil
+}
+
+// clampConfidence keeps model confidence in 0..1, some answers use percents
+func clampConfidence(value float64) float64 {
+	if value > 1 && value <= 100 {
+		value /= 100
+	}
+	if value < 0 {
+		return 0
+	}
+	if value > 1 {
+		return 1
+	}
+	return value
+}
+
+// parseDuration converts "MM:SS" or "HH:MM:SS" into seconds, 0 if unknown
+func parseDuration(value string) int {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0
+	}
+
+	total := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return total
+}
+
+func parsePlayedAt(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range playedAtLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			continue
+		}
+		// A timestamp from the future means the model misread the date
+		if t.After(time.Now().Add(24 * time.Hour)) {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"valhalla/internal/models"
+)
+
+func TestParseScoreboardJSON(t *testing.T) {
+	endedAt := time.Date(2025, 3, 14, 21, 47, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		raw      string
+		want     *models.Match
+		playedAt *time.Time
+		wantErr  bool
+	}{
+		{
+			name: "object format",
+			raw: `{"match": {"blue_score": 21, "red_score": 9, "duration": "12:34", "game_mode": " Ranked ", "ended_at": "2025-03-14 21:47"},
+				"players": [{"raw_name": "★Shadow★", "player_name": "Shadow", "team": "Blue", "result": "win", "kills": 5, "deaths": 1,
+				"assists": 7, "champion": " Layla ", "gold": 9100, "medal": "mvp", "name_confidence": 0.8, "stats_confidence": 0.9}]}`,
+			want: &models.Match{
+				BlueScore: 21, RedScore: 9, DurationSec: 754, GameMode: "Ranked",
+				Players: []models.PlayerResult{{
+					RawName: "★Shadow★", PlayerName: "Shadow", Team: models.TeamBlue, Result: "WIN", Kills: 5, Deaths: 1, Assists: 7,
+					Champion: "Layla", Gold: 9100, Medal: models.MedalMVP, NameConfidence: 0.8, StatsConfidence: 0.9,
+				}},
+			},
+			playedAt: &endedAt,
+		},
+		{
+			name: "legacy array",
+			raw:  `[{"player_name": "Luna", "result": "LOSE", "kills": 1, "deaths": 2, "assists": 3}]`,
+			want: &models.Match{Players: []models.PlayerResult{
+				{RawName: "Luna", PlayerName: "Luna", Result: "LOSE", Kills: 1, Deaths: 2, Assists: 3},
+			}},
+		},
+		{
+			name: "name from raw name and percent confidence",
+			raw:  `{"players": [{"raw_name": "  Pixel  ", "result": "WIN", "name_confidence": 95, "stats_confidence": -1}]}`,
+			want: &models.Match{Players: []models.PlayerResult{
+				{RawName: "Pixel", PlayerName: "Pixel", Result: "WIN", NameConfidence: 0.95},
+			}},
+		},
+		{
+			name: "unreadable duration and future end time are dropped",
+			raw:  `{"match": {"duration": "soon", "ended_at": "2999-01-01 10:00"}, "players": []}`,
+			want: &models.Match{Players: []models.PlayerResult{}},
+		},
+		{
+			name:    "invalid json",
+			raw:     `{"players": [`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScoreboardJSON([]byte(tt.raw))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if (got.PlayedAt == nil) != (tt.playedAt == nil) || (got.PlayedAt != nil && !got.PlayedAt.Equal(*tt.playedAt)) {
+				t.Errorf("played at = %v, want %v", got.PlayedAt, tt.playedAt)
+			}
+			got.PlayedAt = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
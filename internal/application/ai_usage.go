@@ -0,0 +1,211 @@
+package application
+
+import (
+	"fmt"
+	"time"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+	"valhalla/internal/repository"
+)
+
+type AIUsageService interface {
+	GetUsageReport(guildID string) (*AIUsageReport, error)
+}
+
+// AIBudget caps the scoreboard provider calls, a zero limit is disabled. Failed calls count against
+// the limits as well, refused ones do not
+type AIBudget struct {
+	DailyCalls    int
+	MonthlyCalls  int
+	DailyTokens   int
+	MonthlyTokens int
+
+	GuildDailyCalls   int
+	GuildMonthlyCalls int
+	UserDailyCalls    int
+	UserMonthlyCalls  int
+}
+
+// AIUsageReport is the /ai_usage summary: all calls and the calls of one guild, today and this month
+type AIUsageReport struct {
+	Budget     AIBudget
+	Today      models.AIUsageTotals
+	Month      models.AIUsageTotals
+	GuildToday models.AIUsageTotals
+	GuildMonth models.AIUsageTotals
+	TopUsers   []models.AIUserUsage // this month in the guild
+}
+
+// QuotaExceededError is returned instead of calling the provider once a budget has run out
+type QuotaExceededError struct {
+	Scope   string // aiScopeGlobal, aiScopeGuild or aiScopeUser
+	Monthly bool
+	Tokens  bool
+	Limit   int
+}
+
+func (e *QuotaExceededError) Error() string {
+	period := "Дневной"
+	if e.Monthly {
+		period = "Месячный"
+	}
+	unit := "запросов"
+	if e.Tokens {
+		unit = "токенов"
+	}
+	scope := ""
+	switch e.Scope {
+	case aiScopeGuild:
+		scope = " сервера"
+	case aiScopeUser:
+		scope = " пользователя"
+	}
+	return fmt.Sprintf("%s лимит %s к ИИ%s исчерпан (%d)", period, unit, scope, e.Limit)
+}
+
+const (
+	aiScopeGlobal = ""
+	aiScopeGuild  = "guild"
+	aiScopeUser   = "user"
+)
+
+type AIUsageServiceImpl struct {
+	repo   repository.AIUsage
+	budget AIBudget
+	logger Logger
+}
+
+func NewAIUsageServiceImpl(repo repository.AIUsage, budget AIBudget, logger Logger) *AIUsageServiceImpl {
+	return &AIUsageServiceImpl{repo: repo, budget: budget, logger: logger}
+}
+
+// call runs one provider request for the requester after checking the budgets and records how it went.
+// Concurrent workers check before any of them records, so a limit may be overshot by a few calls
+func (s *AIUsageServiceImpl) call(requester models.MatchSource, operation string, game *games.Profile,
+	fn func() (*models.Match, models.TokenUsage, error)) (*models.Match, error) {
+	usage := models.AIUsage{
+		Operation: operation,
+		Game:      game.ID,
+		Platform:  requester.Platform,
+		GuildID:   requester.GuildID,
+		UserID:    requester.SubmitterID,
+		UserName:  requester.SubmitterName,
+	}
+
+	if err := s.checkBudget(requester); err != nil {
+		usage.Outcome = models.AIOutcomeBlocked
+		usage.Error = err.Error()
+		s.record(usage)
+		return nil, err
+	}
+
+	start := time.Now()
+	match, tokens, err := fn()
+	usage.Latency = time.Since(start)
+	usage.TokenUsage = tokens
+	usage.Outcome = models.AIOutcomeSuccess
+	if err != nil {
+		usage.Outcome = models.AIOutcomeFailure
+		usage.Error = err.Error()
+	}
+	s.record(usage)
+
+	return match, err
+}
+
+func (s *AIUsageServiceImpl) record(usage models.AIUsage) {
+	if err := s.repo.RecordAIUsage(usage); err != nil {
+		s.logger.Error("%v", err)
+	}
+}
+
+func (s *AIUsageServiceImpl) checkBudget(requester models.MatchSource) error {
+	day, month := usagePeriods(time.Now())
+
+	checks := []struct {
+		scope   string
+		monthly bool
+		calls   int
+		tokens  int
+	}{
+		{aiScopeGlobal, false, s.budget.DailyCalls, s.budget.DailyTokens},
+		{aiScopeGlobal, true, s.budget.MonthlyCalls, s.budget.MonthlyTokens},
+		{aiScopeGuild, false, s.budget.GuildDailyCalls, 0},
+		{aiScopeGuild, true, s.budget.GuildMonthlyCalls, 0},
+		{aiScopeUser, false, s.budget.UserDailyCalls, 0},
+		{aiScopeUser, true, s.budget.UserMonthlyCalls, 0},
+	}
+
+	for _, c := range checks {
+		if c.calls <= 0 && c.tokens <= 0 {
+			continue
+		}
+
+		filter := models.AIUsageFilter{Since: day}
+		if c.monthly {
+			filter.Since = month
+		}
+		switch c.scope {
+		case aiScopeGuild:
+			if requester.GuildID == "" {
+				continue
+			}
+			filter.GuildID = requester.GuildID
+		case aiScopeUser:
+			if requester.SubmitterID == "" {
+				continue
+			}
+			filter.Platform = requester.Platform
+			filter.UserID = requester.SubmitterID
+		}
+
+		totals, err := s.repo.GetAIUsageTotals(filter)
+		if err != nil {
+			// An unavailable usage table must not stop uploads
+			s.logger.Error("%v", err)
+			return nil
+		}
+		if c.calls > 0 && totals.Calls >= c.calls {
+			return &QuotaExceededError{Scope: c.scope, Monthly: c.monthly, Limit: c.calls}
+		}
+		if c.tokens > 0 && totals.TotalTokens >= c.tokens {
+			return &QuotaExceededError{Scope: c.scope, Monthly: c.monthly, Tokens: true, Limit: c.tokens}
+		}
+	}
+	return nil
+}
+
+func (s *AIUsageServiceImpl) GetUsageReport(guildID string) (*AIUsageReport, error) {
+	day, month := usagePeriods(time.Now())
+	report := &AIUsageReport{Budget: s.budget}
+
+	totals := []struct {
+		dst    *models.AIUsageTotals
+		filter models.AIUsageFilter
+	}{
+		{&report.Today, models.AIUsageFilter{Since: day}},
+		{&report.Month, models.AIUsageFilter{Since: month}},
+		{&report.GuildToday, models.AIUsageFilter{Since: day, GuildID: guildID}},
+		{&report.GuildMonth, models.AIUsageFilter{Since: month, GuildID: guildID}},
+	}
+	for _, t := range totals {
+		res, err := s.repo.GetAIUsageTotals(t.filter)
+		if err != nil {
+			return nil, err
+		}
+		*t.dst = *res
+	}
+
+	topUsers, err := s.repo.GetAIUsageByUser(models.AIUsageFilter{Since: month, GuildID: guildID}, aiUsageTopUsers)
+	if err != nil {
+		return nil, err
+	}
+	report.TopUsers = topUsers
+	return report, nil
+}
+
+// usagePeriods returns the start of the current day and month, budgets reset at local midnight
+func usagePeriods(now time.Time) (time.Time, time.Time) {
+	y, m, d := now.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+}
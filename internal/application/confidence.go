@@ -0,0 +1,44 @@
+package application
+
+import (
+	"fmt"
+	"valhalla/internal/models"
+)
+
+// ReviewPolicy decides which parsed matches need a human before they count
+type ReviewPolicy struct {
+	// AutoApproveConfidence is the minimum name and stats confidence every player needs for the match
+	// to be approved without review, 0 sends every match to the review queue
+	AutoApproveConfidence float64
+}
+
+// reviewReasons explains why a parsed match has to wait for a reviewer, none means it can count right away
+func (p ReviewPolicy) reviewReasons(m *models.Match) []string {
+	if p.AutoApproveConfidence <= 0 {
+		return []string{"автоподтверждение выключено"}
+	}
+
+	var reasons []string
+	if unresolved := countUnresolved(m); unresolved > 0 {
+		reasons = append(reasons, fmt.Sprintf("неоднозначные ники: %d", unresolved))
+	}
+	if m.PossibleDuplicateOf != 0 {
+		reasons = append(reasons, fmt.Sprintf("возможный дубликат матча #%d", m.PossibleDuplicateOf))
+	}
+	return append(reasons, lowConfidenceFields(m, p.AutoApproveConfidence)...)
+}
+
+// lowConfidenceFields lists values the provider was unsure about. A confidence that was not
+// reported at all counts as low, so providers without confidence always go through review
+func lowConfidenceFields(m *models.Match, threshold float64) []string {
+	var fields []string
+	for _, pr := range m.Players {
+		if pr.NameConfidence < threshold {
+			fields = append(fields, fmt.Sprintf("ник «%s» (%.2f)", pr.RawName, pr.NameConfidence))
+		}
+		if pr.StatsConfidence < threshold {
+			fields = append(fields, fmt.Sprintf("K/D/A %s %d/%d/%d (%.2f)", pr.PlayerName, pr.Kills, pr.Deaths, pr.Assists, pr.StatsConfidence))
+		}
+	}
+	return fields
+}
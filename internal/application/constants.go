@@ -1,8 +1,26 @@
 package application
 
+import "time"
+
 const (
+	// Screenshot download
+	imageDownloadTimeout = 10 * time.Second
+	maxImageSize         = 10 * 1024 * 1024
+
+	// Ingestion queue: polling, worker lease and retry schedule
+	ingestionPollInterval      = 5 * time.Second
+	ingestionJobLease          = 10 * time.Minute
+	defaultIngestionWorkers    = 3
+	defaultIngestionAttempts   = 5
+	defaultIngestionRetryDelay = 30 * time.Second
+	maxIngestionRetryDelay     = 30 * time.Minute
+
+	// Requesters listed in the AI usage report
+	aiUsageTopUsers = 10
+
 	// History limits
-	defaultHistoryLimit = 10
+	defaultHistoryLimit   = 10
+	submitterHistoryLimit = 15
 
 	// Google Sheets configuration
 	sheetsHeaderColor     = "FFD700" // Gold
@@ -14,8 +32,38 @@ const (
 	// Match signature generation
 	signatureSeparator = "|"
 
+	// Fuzzy duplicate detection: how far back to look and how much OCR noise to tolerate
+	duplicateSearchWindow = 14 * 24 * time.Hour
+	kdaTolerance          = 1
+	maxKDAMismatches      = 1
+
+	// dHash bit differences: up to perceptualDuplicateThreshold the screenshot is the same scoreboard
+	// re-compressed or re-cropped, up to perceptualHashThreshold it is flagged as a possible duplicate
+	perceptualDuplicateThreshold = 2
+	perceptualHashThreshold      = 6
+
+	// Scoreboard validation, the shape and limits come from the game profile
+	maxParseRetries = 2
+
+	// Review table formatting
+	tableSeparator = "|"
+
 	// Player statistics
-	minDeathsForKDA = 1
+	topHeroesLimit = 3
+
+	// Team Elo: every player starts at initialRating, a match moves a team by at most ratingK
+	initialRating      = 1500.0
+	ratingK            = 32.0
+	ratingHistoryLimit = 5
+
+	// Seasons
+	seasonNameMaxLength = 64
+
+	// Head-to-head and duo records: recent matches shown, teammates listed on /profile and the shared
+	// matches a teammate needs to be ranked
+	pairRecentMatches = 5
+	teammatesLimit    = 3
+	minTeammateGames  = 3
 
 	// Excel report configuration
 	excelSheetName       = "Статистика"
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// EditMatch replaces the results of a stored match with a table edited by an admin, whatever its status.
+// A name written as "#ID" reassigns the row to that player. Every edit is kept in the audit trail
+func (s *MatchServiceImpl) EditMatch(id int, table, adminID string) (*models.Match, []string, error) {
+	match, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("матч #%d не найден", id)
+	}
+	if err := s.closedSeasonError(match); err != nil {
+		return nil, nil, err
+	}
+
+	players, err := parseResultsTable(table, match.Players)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.bindPlayerRefs(players); err != nil {
+		return nil, nil, err
+	}
+
+	edited := &models.Match{Players: players}
+	if violations := validateMatch(edited, games.Resolve(match.Game)); len(violations) > 0 {
+		return nil, nil, &MatchValidationError{Violations: violations}
+	}
+	if err := s.resolvePlayers(edited); err != nil {
+		return nil, nil, err
+	}
+	if err := duplicatePlayersError(edited); err != nil {
+		return nil, nil, err
+	}
+	// Pending matches resolve names on the review card, stored ones must not become ambiguous
+	if match.Status != models.MatchStatusPending {
+		if err := ambiguousNamesError(edited); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	changes := diffMatches(match, edited)
+	if len(changes) == 0 {
+		return nil, nil, fmt.Errorf("изменений нет")
+	}
+
+	signature := generateSignature(edited)
+	if signature != match.MatchSignature {
+		duplicateID, err := s.repo.FindBySignature(signature)
+		if err != nil {
+			return nil, nil, err
+		}
+		if duplicateID != 0 {
+			return nil, nil, fmt.Errorf("матч с такими результатами уже есть: #%d", duplicateID)
+		}
+	}
+	if err := s.createNewPlayers(edited); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.repo.ReplaceResults(id, edited.MatchSignature, edited.Players); err != nil {
+		return nil, nil, err
+	}
+	s.recordEdit(match, models.MatchEditManual, adminID, changes)
+
+	s.logger.Info("Match %d edited by %s: %s", id, adminID, strings.Join(changes, "; "))
+
+	updated, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if match.Status == models.MatchStatusApproved {
+		s.onResultsChanged(match.Game, match, updated)
+	}
+	return updated, changes, nil
+}
+
+func (s *MatchServiceImpl) GetMatchEdits(id int) ([]models.MatchEdit, error) {
+	return s.repo.GetMatchEdits(id)
+}
+
+// recordEdit stores an audit entry, a failure is only logged since the results are already changed
+func (s *MatchServiceImpl) recordEdit(before *models.Match, kind, editorID string, changes []string) {
+	edit := models.MatchEdit{
+		MatchID:  before.ID,
+		Kind:     kind,
+		EditedBy: editorID,
+		Changes:  changes,
+		Previous: FormatResultsTable(before.Players),
+	}
+	if err := s.repo.AddMatchEdit(edit); err != nil {
+		s.logger.Error("%v", err)
+	}
+}
+
+// bindPlayerRefs binds rows whose name is written as "#ID" to that player and shows the player's name instead
+func (s *MatchServiceImpl) bindPlayerRefs(players []models.PlayerResult) error {
+	for i := range players {
+		p := &players[i]
+		if !strings.HasPrefix(p.PlayerName, "#") {
+			continue
+		}
+		playerID, err := strconv.Atoi(strings.TrimPrefix(p.PlayerName, "#"))
+		if err != nil || playerID <= 0 {
+			return fmt.Errorf("неверный ID игрока %q", p.PlayerName)
+		}
+		name, err := s.repo.GetPlayerNameByID(playerID)
+		if err != nil {
+			return fmt.Errorf("игрок #%d не найден", playerID)
+		}
+		p.PlayerID = playerID
+		p.PlayerName = name
+		p.RawName = name
+		p.Candidates = nil
+	}
+	return nil
+}
+
+// ambiguousNamesError rejects typed names that fit several players, an edit must not leave a stored match unresolved
+func ambiguousNamesError(m *models.Match) error {
+	lines := ambiguousNames(m)
+	if len(lines) == 0 {
+		return nil
+	}
+	return fmt.Errorf("неоднозначные ники, укажите игрока как #ID:\n%s", strings.Join(lines, "\n"))
+}
+
+// duplicatePlayersError rejects rows bound to the same player, different names can point to one player
+// through "#ID" or an alias
+func duplicatePlayersError(m *models.Match) error {
+	seen := make(map[int]string, len(m.Players))
+	for _, p := range m.Players {
+		if p.PlayerID == 0 {
+			continue
+		}
+		if prev, ok := seen[p.PlayerID]; ok {
+			return fmt.Errorf("%q и %q — один и тот же игрок #%d, он может быть в матче только один раз", prev, p.PlayerName, p.PlayerID)
+		}
+		seen[p.PlayerID] = p.PlayerName
+	}
+	return nil
+}
+
+// ambiguousNames lists the unresolved names of a match with their candidates, one line per name
+func ambiguousNames(m *models.Match) []string {
+	var lines []string
+	for _, p := range m.Players {
+		if !p.Unresolved() {
+			continue
+		}
+		var options []string
+		for _, c := range p.Candidates {
+			options = append(options, fmt.Sprintf("%s #%d", c.Name, c.PlayerID))
+		}
+		lines = append(lines, fmt.Sprintf("%q: %s", p.PlayerName, strings.Join(options, ", ")))
+	}
+	return lines
+}
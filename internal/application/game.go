@@ -0,0 +1,61 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// GetGame returns the game played in a channel: the channel selection, then the server one, then the default
+func (s *MatchServiceImpl) GetGame(guildID, channelID string) *games.Profile {
+	sel, err := s.repo.GetGameSelection(guildID, channelID)
+	if err != nil {
+		s.logger.Warn("failed to get game selection: %v", err)
+		return s.defaultGame
+	}
+	if sel == nil {
+		return s.defaultGame
+	}
+
+	game, ok := games.Get(sel.Game)
+	if !ok {
+		s.logger.Warn("unknown game %q selected for %s %s", sel.Game, sel.Scope, sel.ScopeID)
+		return s.defaultGame
+	}
+	return game
+}
+
+func (s *MatchServiceImpl) GetGameSelection(guildID, channelID string) (*models.GameSelection, error) {
+	return s.repo.GetGameSelection(guildID, channelID)
+}
+
+func (s *MatchServiceImpl) SetGame(scope, scopeID, gameID, adminID string) (*games.Profile, error) {
+	game, ok := games.Get(gameID)
+	if !ok {
+		return nil, fmt.Errorf("неизвестная игра %q, доступны: %s", gameID, strings.Join(games.IDs(), ", "))
+	}
+	if scope != models.GameScopeGuild && scope != models.GameScopeChannel {
+		return nil, fmt.Errorf("неизвестная область %q", scope)
+	}
+
+	sel := models.GameSelection{Scope: scope, ScopeID: scopeID, Game: game.ID, SelectedBy: adminID}
+	if err := s.repo.SetGameSelection(sel); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Game of %s %s set to %s by %s", scope, scopeID, game.ID, adminID)
+	return game, nil
+}
+
+func (s *MatchServiceImpl) ResetGame(scope, scopeID string) error {
+	if err := s.repo.DeleteGameSelection(scope, scopeID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("для этой области игра не выбрана")
+		}
+		return err
+	}
+	return nil
+}
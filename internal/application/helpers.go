@@ -1,5 +1,13 @@
 package application
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"valhalla/internal/models"
+)
+
 func calculateWinRate(wins, matches int) float64 {
 	if matches == 0 {
 		return 0.0
@@ -7,14 +15,6 @@ func calculateWinRate(wins, matches int) float64 {
 	return (float64(wins) / float64(matches)) * 100
 }
 
-func calculateKDA(kills, deaths, assists int) float64 {
-	d := deaths
-	if d == 0 {
-		d = minDeathsForKDA
-	}
-	return float64(kills+assists) / float64(d)
-}
-
 func comparePlayersByPriority(p1, p2 *PlayerStats) bool {
 	if p1.Matches != p2.Matches {
 		return p1.Matches > p2.Matches
@@ -28,7 +28,179 @@ func comparePlayersByPriority(p1, p2 *PlayerStats) bool {
 	}
 
 	// Finally by KDA
-	kda1 := calculateKDA(p1.Kills, p1.Deaths, p1.Assists)
-	kda2 := calculateKDA(p2.Kills, p2.Deaths, p2.Assists)
-	return kda1 > kda2
+	return p1.KDA > p2.KDA
+}
+
+func averagePerMatch(total, matches int) float64 {
+	if matches == 0 {
+		return 0.0
+	}
+	return float64(total) / float64(matches)
+}
+
+// AvgTeamfightPct returns the average teamfight participation, 0 without matches
+func (p *PlayerStats) AvgTeamfightPct() float64 {
+	if p.Matches == 0 {
+		return 0.0
+	}
+	return p.TeamfightPct / float64(p.Matches)
+}
+
+// TopHeroes returns up to limit most played heroes, most played first
+func (p *PlayerStats) TopHeroes(limit int) []string {
+	heroes := p.Heroes
+	names := make([]string, 0, len(heroes))
+	for name := range heroes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if heroes[names[i]] != heroes[names[j]] {
+			return heroes[names[i]] > heroes[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > limit {
+		names = names[:limit]
+	}
+	return names
+}
+
+func sheetHeaders() []interface{} {
+	return []interface{}{"Rank", "ID", "Player", "Matches", "Wins", "Losses", "WinRate %", "KDA", "Rating",
+		"Avg Gold", "Avg Hero Dmg", "Avg Dmg Taken", "Avg Turret Dmg", "Teamfight %", "MVP", "Top Heroes"}
+}
+
+// FormatResultsTable renders match results as editable lines: "name | WIN | K/D/A | hero"
+func FormatResultsTable(players []models.PlayerResult) string {
+	lines := make([]string, 0, len(players))
+	for _, p := range players {
+		lines = append(lines, formatResultLine(p))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatResultLine(p models.PlayerResult) string {
+	line := fmt.Sprintf("%s %s %s %s %d/%d/%d", p.PlayerName, tableSeparator, p.Result, tableSeparator, p.Kills, p.Deaths, p.Assists)
+	if p.Champion != "" {
+		line += fmt.Sprintf(" %s %s", tableSeparator, p.Champion)
+	}
+	return line
+}
+
+// parseResultsTable parses lines produced by FormatResultsTable. Each line keeps the team and detailed stats
+// of the previous row of the same player, so reordering or removing lines does not move them to someone else
+func parseResultsTable(table string, previous []models.PlayerResult) ([]models.PlayerResult, error) {
+	var typed []models.PlayerResult
+	var heroTyped []bool
+	for n, line := range strings.Split(table, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, tableSeparator)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("строка %d: ожидается формат \"ник | WIN | K/D/A | герой\"", n+1)
+		}
+
+		var p models.PlayerResult
+		p.PlayerName = strings.TrimSpace(parts[0])
+		if p.PlayerName == "" {
+			return nil, fmt.Errorf("строка %d: пустой ник", n+1)
+		}
+
+		p.Result = strings.ToUpper(strings.TrimSpace(parts[1]))
+		if p.Result != "WIN" && p.Result != "LOSE" {
+			return nil, fmt.Errorf("строка %d: результат должен быть WIN или LOSE", n+1)
+		}
+
+		kda := strings.Split(strings.TrimSpace(parts[2]), "/")
+		if len(kda) != 3 {
+			return nil, fmt.Errorf("строка %d: K/D/A должно быть в формате 1/2/3", n+1)
+		}
+		values := make([]int, 3)
+		for i, v := range kda {
+			num, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil || num < 0 {
+				return nil, fmt.Errorf("строка %d: неверное значение K/D/A %q", n+1, v)
+			}
+			values[i] = num
+		}
+		p.Kills, p.Deaths, p.Assists = values[0], values[1], values[2]
+
+		if len(parts) > 3 {
+			p.Champion = strings.TrimSpace(parts[3])
+		}
+		typed = append(typed, p)
+		heroTyped = append(heroTyped, len(parts) > 3)
+	}
+
+	if len(typed) == 0 {
+		return nil, fmt.Errorf("таблица пуста")
+	}
+
+	counterparts := previousRows(typed, previous)
+	players := make([]models.PlayerResult, len(typed))
+	for i, t := range typed {
+		var p models.PlayerResult
+		if j := counterparts[i]; j >= 0 {
+			p = previous[j]
+			p.ID = 0
+		}
+
+		// Values typed by the reviewer are certain
+		if t.PlayerName != p.PlayerName {
+			p.PlayerID = 0
+			p.Candidates = nil
+			p.RawName = t.PlayerName
+			p.NameConfidence = 1
+		}
+		p.PlayerName = t.PlayerName
+		p.Result = t.Result
+		if p.Kills != t.Kills || p.Deaths != t.Deaths || p.Assists != t.Assists {
+			p.StatsConfidence = 1
+		}
+		p.Kills, p.Deaths, p.Assists = t.Kills, t.Deaths, t.Assists
+		if heroTyped[i] {
+			p.Champion = t.Champion
+		}
+		players[i] = p
+	}
+	return players, nil
+}
+
+// previousRows pairs typed lines with the previous rows they were edited from, -1 marks a new line.
+// A line is paired by name or "#ID" first, then a renamed line by its unchanged result and K/D/A
+func previousRows(typed, previous []models.PlayerResult) []int {
+	counterparts := make([]int, len(typed))
+	used := make([]bool, len(previous))
+	for i, t := range typed {
+		counterparts[i] = -1
+		for j, p := range previous {
+			if !used[j] && (strings.EqualFold(t.PlayerName, p.PlayerName) || isPlayerRef(t.PlayerName, p.PlayerID)) {
+				counterparts[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	for i, t := range typed {
+		if counterparts[i] >= 0 {
+			continue
+		}
+		for j, p := range previous {
+			if !used[j] && strings.EqualFold(t.Result, p.Result) &&
+				t.Kills == p.Kills && t.Deaths == p.Deaths && t.Assists == p.Assists {
+				counterparts[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+	return counterparts
+}
+
+func isPlayerRef(name string, playerID int) bool {
+	return playerID != 0 && name == fmt.Sprintf("#%d", playerID)
 }
@@ -0,0 +1,254 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"valhalla/internal/models"
+	"valhalla/internal/repository"
+)
+
+type IngestionService interface {
+	Enqueue(jobs []models.IngestionJob) error
+	GetJobsByReply(channelID, messageID string) ([]models.IngestionJob, error)
+
+	// OnUpdate registers a callback for every job that finished or was rescheduled
+	OnUpdate(fn func(job models.IngestionJob))
+	Run(ctx context.Context)
+}
+
+// IngestionConfig sizes the worker pool and the retry schedule
+type IngestionConfig struct {
+	Workers     int
+	MaxAttempts int
+	RetryDelay  time.Duration
+}
+
+type IngestionServiceImpl struct {
+	repo    repository.Ingestion
+	matches MatchService
+	cfg     IngestionConfig
+	logger  Logger
+
+	wake     chan struct{}
+	mu       sync.RWMutex
+	onUpdate func(job models.IngestionJob)
+}
+
+func NewIngestionServiceImpl(repo repository.Ingestion, matches MatchService, cfg IngestionConfig, logger Logger) *IngestionServiceImpl {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultIngestionWorkers
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultIngestionAttempts
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultIngestionRetryDelay
+	}
+	return &IngestionServiceImpl{
+		repo:    repo,
+		matches: matches,
+		cfg:     cfg,
+		logger:  logger,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+func (s *IngestionServiceImpl) Enqueue(jobs []models.IngestionJob) error {
+	for i := range jobs {
+		jobs[i].MaxAttempts = s.cfg.MaxAttempts
+	}
+	if err := s.repo.CreateJobs(jobs); err != nil {
+		return err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *IngestionServiceImpl) GetJobsByReply(channelID, messageID string) ([]models.IngestionJob, error) {
+	return s.repo.GetJobsByReply(channelID, messageID)
+}
+
+func (s *IngestionServiceImpl) OnUpdate(fn func(job models.IngestionJob)) {
+	s.mu.Lock()
+	s.onUpdate = fn
+	s.mu.Unlock()
+}
+
+// Run starts the worker pool and blocks until ctx is cancelled. Jobs queued before a restart are picked up
+// on the first poll, jobs that were running are taken over once their lease expires
+func (s *IngestionServiceImpl) Run(ctx context.Context) {
+	s.logger.Info("Ingestion workers started: %d", s.cfg.Workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < s.cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *IngestionServiceImpl) work(ctx context.Context) {
+	ticker := time.NewTicker(ingestionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.repo.ClaimJob(ingestionJobLease)
+		if err != nil {
+			s.logger.Error("failed to claim ingestion job: %v", err)
+		}
+		if job != nil {
+			s.process(job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *IngestionServiceImpl) process(job *models.IngestionJob) {
+	if job.Attempts > job.MaxAttempts {
+		// Taken over from a worker that died on its last attempt
+		job.Status = models.JobStatusFailed
+		job.Errors = append(job.Errors, "обработка прервалась, попытки исчерпаны")
+		s.finish(job)
+		return
+	}
+
+	matches, err := s.matches.ProcessImageFromURL(job.Source.AttachmentURL, job.Source)
+
+	// Scoreboards stored by an earlier attempt come back as duplicates of this job's own matches
+	stored := job.MatchIDs
+	job.MatchIDs = nil
+	job.Duplicates = 0
+	job.Errors = nil
+	for _, m := range matches {
+		job.MatchIDs = append(job.MatchIDs, m.ID)
+	}
+
+	var transient []string
+	for _, e := range unjoinErrors(err) {
+		var dupErr *DuplicateMatchError
+		switch {
+		case errors.As(e, &dupErr) && slices.Contains(stored, dupErr.MatchID):
+			job.MatchIDs = append(job.MatchIDs, dupErr.MatchID)
+		case errors.Is(e, ErrDuplicateMatch):
+			job.Duplicates++
+		case isPermanentIngestionError(e):
+			job.Errors = append(job.Errors, describeIngestionError(e))
+		default:
+			transient = append(transient, describeIngestionError(e))
+		}
+	}
+
+	// A partially stored collage is retried too: the next attempt only parses the scoreboards still missing
+	if len(transient) > 0 && job.Attempts < job.MaxAttempts {
+		job.Status = models.JobStatusQueued
+		job.LastError = strings.Join(transient, "; ")
+		job.NextAttemptAt = time.Now().Add(s.retryDelay(job.Attempts))
+		s.logger.Warn("Ingestion job %d failed (attempt %d/%d), retrying at %s: %s",
+			job.ID, job.Attempts, job.MaxAttempts, job.NextAttemptAt.Format(time.RFC3339), job.LastError)
+		if err := s.repo.RetryJob(*job); err != nil {
+			s.logger.Error("%v", err)
+			return
+		}
+		s.notify(*job)
+		return
+	}
+
+	job.Errors = append(job.Errors, transient...)
+	job.Status = models.JobStatusDone
+	if len(job.MatchIDs) == 0 && job.Duplicates == 0 && len(job.Errors) > 0 {
+		job.Status = models.JobStatusFailed
+	}
+	s.finish(job)
+}
+
+func (s *IngestionServiceImpl) finish(job *models.IngestionJob) {
+	if err := s.repo.FinishJob(*job); err != nil {
+		s.logger.Error("%v", err)
+		return
+	}
+	s.logger.Info("Ingestion job %d %s after %d attempt(s): %d match(es), %d duplicate(s), %d error(s)",
+		job.ID, job.Status, job.Attempts, len(job.MatchIDs), job.Duplicates, len(job.Errors))
+	s.notify(*job)
+}
+
+func (s *IngestionServiceImpl) notify(job models.IngestionJob) {
+	s.mu.RLock()
+	fn := s.onUpdate
+	s.mu.RUnlock()
+	if fn != nil {
+		fn(job)
+	}
+}
+
+// retryDelay doubles the base delay with every failed attempt, up to maxIngestionRetryDelay
+func (s *IngestionServiceImpl) retryDelay(attempt int) time.Duration {
+	delay := s.cfg.RetryDelay
+	for i := 1; i < attempt && delay < maxIngestionRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxIngestionRetryDelay {
+		delay = maxIngestionRetryDelay
+	}
+	return delay
+}
+
+// isPermanentIngestionError tells apart failures a retry cannot fix from rate limits, timeouts and outages.
+// An exhausted budget only resets at midnight, far beyond the retry schedule
+func isPermanentIngestionError(err error) bool {
+	var validationErr *MatchValidationError
+	var quotaErr *QuotaExceededError
+	return errors.As(err, &validationErr) ||
+		errors.As(err, &quotaErr) ||
+		errors.Is(err, ErrImageUnavailable) ||
+		errors.Is(err, ErrUnsupportedImage)
+}
+
+// describeIngestionError turns a processing error into the line shown to the uploader
+func describeIngestionError(err error) string {
+	var regionErr *RegionError
+	if errors.As(err, &regionErr) {
+		return fmt.Sprintf("табло %d: %s", regionErr.Region, describeIngestionError(regionErr.Err))
+	}
+
+	var validationErr *MatchValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return "распознавание не прошло проверку\n   • " + strings.Join(validationErr.Violations, "\n   • ")
+	case errors.Is(err, ErrImageUnavailable):
+		return "скриншот больше недоступен по ссылке"
+	case errors.Is(err, ErrUnsupportedImage):
+		return "не удалось прочитать изображение"
+	default:
+		return err.Error()
+	}
+}
+
+// unjoinErrors unpacks an errors.Join result into its parts
+func unjoinErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
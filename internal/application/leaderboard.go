@@ -0,0 +1,201 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// Leaderboard sort orders
+const (
+	SortRating  = "rating"
+	SortWinRate = "winrate"
+	SortKDA     = "kda"
+	SortKills   = "kills"   // average kills per match
+	SortDeaths  = "deaths"  // average deaths per match, fewest first
+	SortAssists = "assists" // average assists per match
+	SortMatches = "matches" // matches, then win rate, then KDA
+
+	// UseDefaultMinGames applies the configured minimum instead of an explicit one
+	UseDefaultMinGames = -1
+)
+
+// LeaderboardSorts lists the orders in the way they are offered to users
+var LeaderboardSorts = []string{SortRating, SortWinRate, SortKDA, SortKills, SortDeaths, SortAssists, SortMatches}
+
+// LeaderboardConfig holds the leaderboard defaults: the sort of the Google sheet and the minimum number of
+// matches a player needs to be listed, so a single won match does not top the win rate
+type LeaderboardConfig struct {
+	SheetSort string
+	MinGames  int
+}
+
+// LeaderboardQuery selects and orders a leaderboard, zero values do not filter
+type LeaderboardQuery struct {
+	Season   string // closed season name, the current standings when empty
+	SortBy   string
+	MinGames int
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+	Hero     string
+	Role     string // main role of the linked profile
+}
+
+// filtered tells whether the query needs the matches rather than the stored standings
+func (q LeaderboardQuery) filtered() bool {
+	return !q.From.IsZero() || !q.To.IsZero() || q.Hero != "" || q.Role != ""
+}
+
+// GetLeaderboard returns the standings selected by the query in its order
+func (s *MatchServiceImpl) GetLeaderboard(game *games.Profile, q LeaderboardQuery) ([]*PlayerStats, error) {
+	if q.SortBy == "" {
+		q.SortBy = SortMatches
+	}
+	if !IsLeaderboardSort(q.SortBy) {
+		return nil, fmt.Errorf("неизвестная сортировка %q, доступны: %s", q.SortBy, strings.Join(LeaderboardSorts, ", "))
+	}
+	if q.MinGames == UseDefaultMinGames {
+		q.MinGames = s.leaderboard.MinGames
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return nil, fmt.Errorf("начало периода должно быть раньше конца")
+	}
+
+	var statsList []*PlayerStats
+	var err error
+	if q.filtered() {
+		statsList, err = s.filteredStats(game, q)
+	} else {
+		statsList, err = s.seasonStats(game, q.Season, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	listed := statsList[:0]
+	for _, st := range statsList {
+		if st.Matches >= q.MinGames {
+			listed = append(listed, st)
+		}
+	}
+	sortStats(listed, q.SortBy)
+	return listed, nil
+}
+
+// filteredStats aggregates the matches of the chosen season that pass the filters
+func (s *MatchServiceImpl) filteredStats(game *games.Profile, q LeaderboardQuery) ([]*PlayerStats, error) {
+	filter := models.StatsFilter{Game: game.ID, From: q.From, To: q.To, Hero: strings.TrimSpace(q.Hero), Role: strings.TrimSpace(q.Role)}
+
+	var seasonStart, seasonEnd time.Time
+	var ratings map[int]float64
+	season, err := s.findSeason(q.Season)
+	if err != nil {
+		return nil, err
+	}
+	if season == nil || season.Status == models.SeasonActive {
+		if seasonStart, err = s.repo.GetSeasonStartDate(); err != nil {
+			return nil, err
+		}
+		if ratings, err = s.currentRatings(game, 0); err != nil {
+			return nil, err
+		}
+	} else {
+		seasonStart, seasonEnd = season.StartedAt, *season.EndedAt
+		// A closed season shows the ratings frozen with its standings
+		standings, err := s.repo.GetSeasonStandings(season.ID, game.ID)
+		if err != nil {
+			return nil, err
+		}
+		ratings = make(map[int]float64, len(standings))
+		for _, st := range standings {
+			ratings[st.PlayerID] = st.Rating
+		}
+	}
+
+	if filter.From.Before(seasonStart) {
+		filter.From = seasonStart
+	}
+	if !seasonEnd.IsZero() && (filter.To.IsZero() || filter.To.After(seasonEnd)) {
+		filter.To = seasonEnd
+	}
+
+	aggregates, err := s.repo.AggregatePlayerStats(filter)
+	if err != nil {
+		return nil, err
+	}
+	statsList := make([]*PlayerStats, 0, len(aggregates))
+	for _, a := range aggregates {
+		statsList = append(statsList, statsFromAggregate(game, a, ratings))
+	}
+	return statsList, nil
+}
+
+// sortStats orders a leaderboard, players tied on the requested criterion fall back to the matches order
+func sortStats(statsList []*PlayerStats, sortBy string) {
+	sort.SliceStable(statsList, func(i, j int) bool {
+		a, b := sortValue(statsList[i], sortBy), sortValue(statsList[j], sortBy)
+		if a != b {
+			return a > b
+		}
+		return comparePlayersByPriority(statsList[i], statsList[j])
+	})
+}
+
+// sortValue is the value a leaderboard is ordered by, higher ranks first
+func sortValue(st *PlayerStats, sortBy string) float64 {
+	switch sortBy {
+	case SortRating:
+		return st.Rating
+	case SortWinRate:
+		return calculateWinRate(st.Wins, st.Matches)
+	case SortKDA:
+		return st.KDA
+	case SortKills:
+		return averagePerMatch(st.Kills, st.Matches)
+	case SortDeaths:
+		return -averagePerMatch(st.Deaths, st.Matches)
+	case SortAssists:
+		return averagePerMatch(st.Assists, st.Matches)
+	default:
+		return float64(st.Matches)
+	}
+}
+
+// IsLeaderboardSort tells whether sortBy is one of the Sort* orders
+func IsLeaderboardSort(sortBy string) bool {
+	for _, known := range LeaderboardSorts {
+		if sortBy == known {
+			return true
+		}
+	}
+	return false
+}
+
+func statsFromAggregate(game *games.Profile, a models.PlayerAggregate, ratings map[int]float64) *PlayerStats {
+	st := &PlayerStats{
+		ID:           a.PlayerID,
+		Name:         a.PlayerName,
+		Matches:      a.Matches,
+		Wins:         a.Wins,
+		Losses:       a.Losses,
+		Kills:        a.Kills,
+		Deaths:       a.Deaths,
+		Assists:      a.Assists,
+		KDA:          game.KDA(a.Kills, a.Deaths, a.Assists),
+		Rating:       initialRating,
+		Gold:         a.Gold,
+		HeroDamage:   a.HeroDamage,
+		DamageTaken:  a.DamageTaken,
+		TurretDamage: a.TurretDamage,
+		TeamfightPct: a.TeamfightPct,
+		MVPs:         a.MVPs,
+		Heroes:       a.Heroes,
+	}
+	if r, ok := ratings[st.ID]; ok {
+		st.Rating = r
+	}
+	return st
+}
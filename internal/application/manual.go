@@ -0,0 +1,79 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// manualDateLayouts are the accepted formats of the /add_match date, read in local time
+var manualDateLayouts = []string{"02.01.2006 15:04", "02.01.2006"}
+
+// AddManualMatch records a match typed in by an admin when there is no readable screenshot. The table uses the
+// review format "name | WIN | K/D/A | hero", names are bound to existing players or create new ones. The match
+// goes through the same validation and duplicate checks as a parsed one and is approved at once
+func (s *MatchServiceImpl) AddManualMatch(game *games.Profile, table, dateStr string, source models.MatchSource) (*models.Match, error) {
+	players, err := parseResultsTable(table, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	match := &models.Match{
+		Game:       game.ID,
+		Manual:     true,
+		Players:    players,
+		Status:     models.MatchStatusApproved,
+		ReviewedBy: source.SubmitterID,
+		Source:     source,
+	}
+	if violations := validateMatch(match, game); len(violations) > 0 {
+		return nil, &MatchValidationError{Violations: violations}
+	}
+
+	if dateStr = strings.TrimSpace(dateStr); dateStr != "" {
+		playedAt, err := parseManualDate(dateStr)
+		if err != nil {
+			return nil, err
+		}
+		match.PlayedAt = &playedAt
+	}
+
+	for i := range match.Players {
+		p := &match.Players[i]
+		p.NameConfidence, p.StatsConfidence = 1, 1
+		p.PlayerID, err = s.repo.EnsurePlayerExists(p.PlayerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve player %q: %w", p.PlayerName, err)
+		}
+	}
+	if err := s.checkDuplicate(match); err != nil {
+		return nil, err
+	}
+
+	matchID, err := s.repo.Create(*match)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Match %d entered manually by %s", matchID, source.SubmitterID)
+	created, err := s.repo.GetByID(matchID)
+	if err != nil {
+		return nil, err
+	}
+	s.onResultsChanged(game.ID, created)
+	return created, nil
+}
+
+func parseManualDate(value string) (time.Time, error) {
+	for _, layout := range manualDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			if t.After(time.Now()) {
+				return time.Time{}, fmt.Errorf("дата матча в будущем")
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("неверный формат даты, используйте ДД.ММ.ГГГГ ЧЧ:ММ")
+}
@@ -2,16 +2,22 @@ package application
 
 import (
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
-	"sort"
 	"strings"
+	"sync"
 	"time"
+	"valhalla/internal/ai"
+	"valhalla/internal/games"
 	"valhalla/internal/models"
 	"valhalla/internal/repository"
 	"valhalla/pkg/sheets"
+	"valhalla/pkg/storage"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -19,20 +25,37 @@ import (
 type MatchServiceImpl struct {
 	repo          repository.Match
 	ai            AIProvider
+	usage         *AIUsageServiceImpl
 	sheetsClient  sheets.Client
+	blobStore     storage.BlobStore
+	policy        ReviewPolicy
+	leaderboard   LeaderboardConfig
+	defaultGame   *games.Profile
 	spreadsheetID string
 	ownerEmail    string
 	logger        Logger
+
+	reparseMu sync.Mutex
+	reparses  map[int]*models.Match // match ID -> fresh parse waiting for confirmation
+
+	ratingMu sync.Mutex // rating replays of concurrent approvals must not interleave
+	statsMu  sync.Mutex // refreshes of the same players would clash on the stored rows
 }
 
-func NewMatchServiceImpl(repo repository.Match, ai AIProvider, sheetsClient sheets.Client, ownerEmail string, logger Logger) *MatchServiceImpl {
+func NewMatchServiceImpl(repo repository.Match, ai AIProvider, usage *AIUsageServiceImpl, sheetsClient sheets.Client, blobStore storage.BlobStore, policy ReviewPolicy, leaderboard LeaderboardConfig, defaultGame *games.Profile, ownerEmail string, logger Logger) *MatchServiceImpl {
 	return &MatchServiceImpl{
 		repo:          repo,
 		ai:            ai,
+		usage:         usage,
 		sheetsClient:  sheetsClient,
+		blobStore:     blobStore,
+		policy:        policy,
+		leaderboard:   leaderboard,
+		defaultGame:   defaultGame,
 		spreadsheetID: "1ZDBqKL1Sgr8-JPXChMafyiHmzHXVJB0aFKXgoTjEfR8",
 		ownerEmail:    ownerEmail,
 		logger:        logger,
+		reparses:      make(map[int]*models.Match),
 	}
 }
 
@@ -45,83 +68,374 @@ type PlayerStats struct {
 	Kills   int
 	Deaths  int
 	Assists int
+	KDA     float64
+	Rating  float64
+
+	Gold         int
+	HeroDamage   int
+	DamageTaken  int
+	TurretDamage int
+	TeamfightPct float64
+	MVPs         int
+	Heroes       map[string]int // hero name -> matches played
 }
 
-func (s *MatchServiceImpl) ProcessImage(data []byte) (int, error) {
+var (
+	// ErrImageUnavailable means the screenshot link is gone or forbidden, retrying will not help
+	ErrImageUnavailable = errors.New("image is no longer available")
+	ErrUnsupportedImage = errors.New("unsupported image")
+)
+
+// RegionError reports a failed scoreboard of a multi-scoreboard screenshot, Region is 1-based
+type RegionError struct {
+	Region int
+	Err    error
+}
+
+func (e *RegionError) Error() string {
+	return fmt.Sprintf("scoreboard %d: %v", e.Region, e.Err)
+}
+
+func (e *RegionError) Unwrap() error {
+	return e.Err
+}
+
+// ProcessImage parses every scoreboard found on a screenshot and stores each one as a separate match.
+// Matches the review policy trusts are approved right away, the rest wait in the review queue and are not
+// counted until approved. When only some scoreboards of a collage fail, the stored matches are returned
+// together with the joined *RegionError values. Uploading the collage again, or retrying it, only parses
+// the scoreboards that are not stored yet, the others are reported as *DuplicateMatchError
+func (s *MatchServiceImpl) ProcessImage(data []byte, source models.MatchSource) ([]*models.Match, error) {
 	hash := sha256.Sum256(data)
 	fileHash := hex.EncodeToString(hash[:])
 
-	exists, err := s.repo.Exists(fileHash, "")
+	if err := s.checkFileHash(fileHash); err != nil {
+		return nil, err
+	}
+
+	regions, err := ai.NewImageProcessor().DetectScoreboards(data)
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	game := s.GetGame(source.GuildID, source.ChannelID)
+	if len(regions) == 1 {
+		match, err := s.processRegion(regions[0], game, fileHash, "", 0, source)
+		if err != nil {
+			return nil, err
+		}
+		s.archiveImage(fileHash, data)
+		return []*models.Match{match}, nil
+	}
+
+	s.logger.Info("Screenshot %s contains %d scoreboards", fileHash[:12], len(regions))
+
+	var matches []*models.Match
+	var errs []error
+	for i, region := range regions {
+		match, err := s.processRegion(region, game, ai.ImageHash(region), fileHash, i, source)
+		if err != nil {
+			errs = append(errs, &RegionError{Region: i + 1, Err: err})
+			continue
+		}
+		matches = append(matches, match)
+	}
+	if len(matches) > 0 {
+		s.archiveImage(fileHash, data)
+	}
+	return matches, errors.Join(errs...)
+}
+
+// detectScoreboards crops the scoreboards out of an archived screenshot, falling back to the whole image
+func (s *MatchServiceImpl) detectScoreboards(data []byte) [][]byte {
+	regions, err := ai.NewImageProcessor().DetectScoreboards(data)
+	if err != nil || len(regions) == 0 {
+		return [][]byte{data}
 	}
-	if exists {
-		return 0, fmt.Errorf("duplicate match detected")
+	return regions
+}
+
+// processRegion parses and stores a single scoreboard. sourceHash is set only for scoreboards cut out of
+// a collage, their own fileHash is the hash of the crop
+func (s *MatchServiceImpl) processRegion(data []byte, game *games.Profile, fileHash, sourceHash string, regionIndex int, source models.MatchSource) (*models.Match, error) {
+	if sourceHash != "" {
+		if err := s.checkFileHash(fileHash); err != nil {
+			return nil, err
+		}
 	}
 
-	match, err := s.ai.ParseImage(data)
+	perceptualHash, err := ai.NewImageProcessor().PerceptualHash(data)
 	if err != nil {
-		return 0, err
+		s.logger.Warn("failed to compute perceptual hash: %v", err)
 	}
-	match.FileHash = fileHash
 
-	matchSig := generateSignature(match)
-	match.MatchSignature = matchSig
-	sigExists, err := s.repo.Exists("", matchSig)
+	// A re-compressed copy of a stored scoreboard is rejected before spending an AI call on it,
+	// a merely similar one is parsed and flagged for review
+	var similarID int
+	if perceptualHash != 0 {
+		id, distance, err := s.repo.FindSimilarMatch(perceptualHash, perceptualHashThreshold)
+		if err != nil {
+			return nil, err
+		}
+		if id != 0 && distance <= perceptualDuplicateThreshold {
+			return nil, &DuplicateMatchError{MatchID: id}
+		}
+		similarID = id
+	}
+
+	match, err := s.parseValidated(data, game, source)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	match.Game = game.ID
+	match.FileHash = fileHash
+	match.SourceHash = sourceHash
+	match.RegionIndex = regionIndex
+	match.PerceptualHash = perceptualHash
+	match.PossibleDuplicateOf = similarID
+	match.Source = source
+	// Backfilled screenshots are parsed long after the game, the post time is closer than the upload time
+	if match.PlayedAt == nil && !source.PostedAt.IsZero() {
+		playedAt := source.PostedAt
+		match.PlayedAt = &playedAt
+	}
+
+	if err := s.resolvePlayers(match); err != nil {
+		return nil, err
 	}
-	if sigExists {
-		return 0, fmt.Errorf("duplicate match detected")
+	if err := s.checkDuplicate(match); err != nil {
+		return nil, err
+	}
+	if err := s.createNewPlayers(match); err != nil {
+		return nil, err
+	}
+
+	match.Status = models.MatchStatusPending
+	if reasons := s.policy.reviewReasons(match); len(reasons) > 0 {
+		match.ReviewNote = strings.Join(reasons, "\n")
+	} else {
+		match.Status = models.MatchStatusApproved
+		match.ReviewedBy = models.ReviewerAuto
 	}
 
 	matchID, err := s.repo.Create(*match)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	if s.sheetsClient != nil {
-		go func() {
-			_, err := s.SyncToGoogleSheet()
-			if err != nil {
-				s.logger.Error("Auto-sync failed: %v", err)
-			}
-		}()
+	created, err := s.repo.GetByID(matchID)
+	if err != nil {
+		return nil, err
+	}
+	if created.Status == models.MatchStatusApproved {
+		s.logger.Info("Match %d approved automatically", matchID)
+		s.onResultsChanged(game.ID, created)
 	}
+	return created, nil
+}
 
-	return matchID, nil
+// checkFileHash rejects an image a live match was already stored from
+func (s *MatchServiceImpl) checkFileHash(fileHash string) error {
+	matchID, err := s.repo.FindByFileHash(fileHash)
+	if err != nil {
+		return err
+	}
+	if matchID != 0 {
+		return &DuplicateMatchError{MatchID: matchID}
+	}
+	return nil
 }
 
-func (s *MatchServiceImpl) ProcessImageFromURL(url string) (int, error) {
+func (s *MatchServiceImpl) ProcessImageFromURL(url string, source models.MatchSource) ([]*models.Match, error) {
+	data, err := downloadImage(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.AttachmentURL == "" {
+		source.AttachmentURL = url
+	}
+	return s.ProcessImage(data, source)
+}
+
+func downloadImage(url string) ([]byte, error) {
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout: imageDownloadTimeout,
 	}
 
 	resp, err := client.Get(url)
 	if err != nil {
-		return 0, fmt.Errorf("failed to download image: %w", err)
+		return nil, fmt.Errorf("failed to download image: %w", err)
 	}
 	defer resp.Body.Close()
 
-	data, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+	if resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: status %d", ErrImageUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize))
 	if err != nil {
-		return 0, fmt.Errorf("failed to read image body: %w", err)
+		return nil, fmt.Errorf("failed to read image body: %w", err)
 	}
+	return data, nil
+}
+
+func (s *MatchServiceImpl) GetPendingMatches() ([]models.Match, error) {
+	return s.repo.GetByStatus(models.MatchStatusPending)
+}
 
-	return s.ProcessImage(data)
+func (s *MatchServiceImpl) GetMatch(id int) (*models.Match, error) {
+	return s.repo.GetByID(id)
 }
 
-func (s *MatchServiceImpl) GetLeaderboard(sortBy string) ([]*PlayerStats, error) {
-	statsList, err := s.calculateStats()
+func (s *MatchServiceImpl) ApproveMatch(id int, reviewerID string) error {
+	match, err := s.getPendingMatch(id)
+	if err != nil {
+		return err
+	}
+	if unresolved := countUnresolved(match); unresolved > 0 {
+		return fmt.Errorf("в матче #%d есть неразрешённые ники (%d), выберите игроков", id, unresolved)
+	}
+	if err := s.repo.SetStatus(id, models.MatchStatusApproved, reviewerID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Match %d approved by %s", id, reviewerID)
+	s.onResultsChanged(match.Game, match)
+	return nil
+}
+
+func (s *MatchServiceImpl) RejectMatch(id int, reviewerID string) error {
+	if _, err := s.getPendingMatch(id); err != nil {
+		return err
+	}
+	if err := s.repo.SetStatus(id, models.MatchStatusRejected, reviewerID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Match %d rejected by %s", id, reviewerID)
+	return nil
+}
+
+// UpdatePendingMatch replaces the results of a pending match with a table edited by a reviewer
+func (s *MatchServiceImpl) UpdatePendingMatch(id int, table string) (*models.Match, error) {
+	match, err := s.getPendingMatch(id)
 	if err != nil {
 		return nil, err
 	}
 
-	sort.Slice(statsList, func(i, j int) bool {
-		return comparePlayersByPriority(statsList[i], statsList[j])
-	})
+	players, err := parseResultsTable(table, match.Players)
+	if err != nil {
+		return nil, err
+	}
 
-	return statsList, nil
+	edited := &models.Match{ID: id, MatchSignature: match.MatchSignature, Players: players}
+	if violations := validateMatch(edited, games.Resolve(match.Game)); len(violations) > 0 {
+		return nil, &MatchValidationError{Violations: violations}
+	}
+	if err := s.resolvePlayers(edited); err != nil {
+		return nil, err
+	}
+	if err := s.checkDuplicate(edited); err != nil {
+		return nil, err
+	}
+	if err := s.createNewPlayers(edited); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceResults(id, edited.MatchSignature, edited.Players); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(id)
+}
+
+// ResolvePlayer binds an ambiguous result of a pending match to the chosen player, or to a new player
+// named as read from the screenshot when playerID is 0. The spelling is remembered as an alias of the choice
+func (s *MatchServiceImpl) ResolvePlayer(matchID, resultID, playerID int, adminID string) (*models.Match, error) {
+	match, err := s.getPendingMatch(matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.PlayerResult
+	for i := range match.Players {
+		if match.Players[i].ID == resultID {
+			result = &match.Players[i]
+			break
+		}
+	}
+	if result == nil || !result.Unresolved() {
+		return nil, fmt.Errorf("ник уже привязан к игроку")
+	}
+
+	alias := ""
+	if playerID != 0 {
+		name, err := s.repo.GetPlayerNameByID(playerID)
+		if err != nil {
+			return nil, fmt.Errorf("игрок с ID %d не найден", playerID)
+		}
+		for _, p := range match.Players {
+			if p.PlayerID == playerID {
+				return nil, fmt.Errorf("игрок %s уже есть в этом матче", name)
+			}
+		}
+		alias = result.PlayerName
+	}
+
+	result.PlayerID = playerID
+	result.Candidates = nil
+	if playerID != 0 {
+		if err := s.checkDuplicate(match); err != nil {
+			return nil, err
+		}
+	} else {
+		playerID, err = s.repo.CreatePlayer(result.PlayerName)
+		if err != nil {
+			return nil, err
+		}
+		result.PlayerID = playerID
+	}
+
+	if err := s.repo.AssignResultPlayer(matchID, resultID, playerID, generateSignature(match), alias, adminID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Result %d of match %d bound to player %d by %s", resultID, matchID, playerID, adminID)
+	return s.repo.GetByID(matchID)
+}
+
+func (s *MatchServiceImpl) GetSubmitterStats() ([]models.SubmitterStats, error) {
+	return s.repo.GetSubmitterStats()
+}
+
+func (s *MatchServiceImpl) GetMatchesBySubmitter(platform, submitterID string) ([]models.Match, error) {
+	return s.repo.GetBySubmitter(platform, submitterID, submitterHistoryLimit)
+}
+
+func (s *MatchServiceImpl) getPendingMatch(id int) (*models.Match, error) {
+	match, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("матч #%d не найден", id)
+	}
+	if match.Status != models.MatchStatusPending {
+		return nil, fmt.Errorf("матч #%d уже обработан (%s)", id, match.Status)
+	}
+	return match, nil
+}
+
+func (s *MatchServiceImpl) autoSyncSheet() {
+	if s.sheetsClient == nil {
+		return
+	}
+	go func() {
+		_, err := s.SyncToGoogleSheet()
+		if err != nil {
+			s.logger.Error("Auto-sync failed: %v", err)
+		}
+	}()
 }
 
 func (s *MatchServiceImpl) GetPlayerList() ([]models.Player, error) {
@@ -142,22 +456,67 @@ func (s *MatchServiceImpl) GetHistoryByID(id int) ([]string, error) {
 	for _, m := range matches {
 		p := m.Players[0]
 		line := fmt.Sprintf("🆔 **%d** | %s | ⚔️ %d/%d/%d | %s",
-			m.ID, p.Result, p.Kills, p.Deaths, p.Assists, m.CreatedAt.Format("02.01"))
+			m.ID, p.Result, p.Kills, p.Deaths, p.Assists, m.Date().Format("02.01"))
+		if p.Champion != "" {
+			line += " | " + p.Champion
+		}
+		if p.Medal == models.MedalMVP {
+			line += " | 🏅 MVP"
+		}
 		lines = append(lines, line)
 	}
 	return lines, nil
 }
 
 func (s *MatchServiceImpl) WipePlayerByID(id int) error {
-	return s.repo.WipePlayerByID(id)
+	if err := s.repo.WipePlayerByID(id); err != nil {
+		return err
+	}
+	for _, game := range games.All() {
+		if err := s.refreshPlayerStats(game, []int{id}); err != nil {
+			s.logger.Error("failed to refresh player stats: %v", err)
+		}
+	}
+	if err := s.RebuildRatings(); err != nil {
+		s.logger.Error("failed to rebuild ratings: %v", err)
+	}
+	s.autoSyncSheet()
+	return nil
 }
 
 func (s *MatchServiceImpl) RenamePlayer(id int, newName string) error {
 	return s.repo.RenamePlayer(id, newName)
 }
 
-func (s *MatchServiceImpl) GetPlayerStats(name string) (*PlayerStats, error) {
-	stats, err := s.calculateStats()
+func (s *MatchServiceImpl) AddAlias(alias string, playerID int, adminID string) error {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return fmt.Errorf("алиас не может быть пустым")
+	}
+	if err := s.repo.AddAlias(alias, playerID, adminID); err != nil {
+		return fmt.Errorf("не удалось добавить алиас: %w", err)
+	}
+
+	s.logger.Info("Alias %q bound to player %d by %s", alias, playerID, adminID)
+	return nil
+}
+
+func (s *MatchServiceImpl) RemoveAlias(alias string) error {
+	if err := s.repo.RemoveAlias(alias); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("алиас %q не найден", alias)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *MatchServiceImpl) GetAliases(playerID int) ([]models.PlayerAlias, error) {
+	return s.repo.GetAliases(playerID)
+}
+
+func (s *MatchServiceImpl) GetPlayerStats(game *games.Profile, name string) (*PlayerStats, error) {
+	stats, err := s.calculateStats(game, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -170,8 +529,8 @@ func (s *MatchServiceImpl) GetPlayerStats(name string) (*PlayerStats, error) {
 	return nil, fmt.Errorf("игрок не найден")
 }
 
-func (s *MatchServiceImpl) GetPlayerStatsByID(id int) (*PlayerStats, error) {
-	stats, err := s.calculateStats()
+func (s *MatchServiceImpl) GetPlayerStatsByID(game *games.Profile, season string, id int) (*PlayerStats, error) {
+	stats, err := s.seasonStats(game, season, id)
 	if err != nil {
 		return nil, err
 	}
@@ -184,26 +543,48 @@ func (s *MatchServiceImpl) GetPlayerStatsByID(id int) (*PlayerStats, error) {
 	return nil, fmt.Errorf("игрок не найден")
 }
 
+// SyncToGoogleSheet exports the leaderboard of every game. The default game stays on the first tab, where
+// it was before games were introduced, every other game gets a tab named after it.
+// The sheet is the unfiltered view of the current standings: it is synced automatically after every change,
+// so it takes only the configured sort and minimum games, never the season, hero, role or date filters of /top
 func (s *MatchServiceImpl) SyncToGoogleSheet() (string, error) {
 	if s.sheetsClient == nil {
 		return "", fmt.Errorf("google sheets service is not configured")
 	}
 
-	statsList, err := s.calculateStats()
-	if err != nil {
-		return "", err
+	for _, game := range games.All() {
+		statsList, err := s.GetLeaderboard(game, LeaderboardQuery{SortBy: s.leaderboard.SheetSort, MinGames: UseDefaultMinGames})
+		if err != nil {
+			return "", err
+		}
+		if err := s.writeSheet(game, sheetRows(statsList)); err != nil {
+			return "", fmt.Errorf("failed to update %s stats: %w", game.Name, err)
+		}
 	}
 
-	sort.Slice(statsList, func(i, j int) bool {
-		return comparePlayersByPriority(statsList[i], statsList[j])
-	})
+	return fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s", s.spreadsheetID), nil
+}
+
+// writeSheet replaces the contents of the game's tab
+func (s *MatchServiceImpl) writeSheet(game *games.Profile, rows [][]interface{}) error {
+	prefix := ""
+	if game.ID != s.defaultGame.ID {
+		if err := s.sheetsClient.EnsureSheet(s.spreadsheetID, game.Name); err != nil {
+			return err
+		}
+		prefix = "'" + strings.ReplaceAll(game.Name, "'", "''") + "'!"
+	}
 
-	var rows [][]interface{}
-	rows = append(rows, []interface{}{"Rank", "ID", "Player", "Matches", "Wins", "Losses", "WinRate %", "KDA"})
+	if err := s.sheetsClient.ClearRange(s.spreadsheetID, prefix+"A1:Z1000"); err != nil {
+		s.logger.Error("failed to clear sheet: %v", err)
+	}
+	return s.sheetsClient.UpdateValues(s.spreadsheetID, prefix+"A1", rows)
+}
 
+func sheetRows(statsList []*PlayerStats) [][]interface{} {
+	rows := [][]interface{}{sheetHeaders()}
 	for i, st := range statsList {
 		winRate := calculateWinRate(st.Wins, st.Matches)
-		kdaRatio := calculateKDA(st.Kills, st.Deaths, st.Assists)
 
 		rows = append(rows, []interface{}{
 			i + 1,
@@ -213,94 +594,53 @@ func (s *MatchServiceImpl) SyncToGoogleSheet() (string, error) {
 			st.Wins,
 			st.Losses,
 			fmt.Sprintf("%.1f%%", winRate),
-			fmt.Sprintf("%.2f", kdaRatio),
+			fmt.Sprintf("%.2f", st.KDA),
+			fmt.Sprintf("%.0f", st.Rating),
+			fmt.Sprintf("%.0f", averagePerMatch(st.Gold, st.Matches)),
+			fmt.Sprintf("%.0f", averagePerMatch(st.HeroDamage, st.Matches)),
+			fmt.Sprintf("%.0f", averagePerMatch(st.DamageTaken, st.Matches)),
+			fmt.Sprintf("%.0f", averagePerMatch(st.TurretDamage, st.Matches)),
+			fmt.Sprintf("%.1f%%", st.AvgTeamfightPct()),
+			st.MVPs,
+			strings.Join(st.TopHeroes(topHeroesLimit), ", "),
 		})
 	}
-
-	if err := s.sheetsClient.ClearRange(s.spreadsheetID, "A1:Z1000"); err != nil {
-		s.logger.Error("failed to clear sheet: %v", err)
-	}
-
-	if err := s.sheetsClient.UpdateValues(s.spreadsheetID, "A1", rows); err != nil {
-		return "", fmt.Errorf("failed to update stats: %w", err)
-	}
-
-	return fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s", s.spreadsheetID), nil
+	return rows
 }
 
-func (s *MatchServiceImpl) calculateStats() ([]*PlayerStats, error) {
-	seasonStart, err := s.repo.GetSeasonStartDate()
+// calculateStats reads the stored current season standings of one game, of a single player when playerID is not 0
+func (s *MatchServiceImpl) calculateStats(game *games.Profile, playerID int) ([]*PlayerStats, error) {
+	aggregates, err := s.repo.GetPlayerAggregates(game.ID, playerID)
 	if err != nil {
 		return nil, err
 	}
 
-	matches, err := s.repo.GetAllAfter(seasonStart)
+	ratings, err := s.currentRatings(game, playerID)
 	if err != nil {
 		return nil, err
 	}
 
-	playerResets, _ := s.repo.GetPlayerResetDates()
-	if playerResets == nil {
-		playerResets = make(map[string]time.Time)
-	}
-
-	statsMap := make(map[int]*PlayerStats)
-
-	for _, m := range matches {
-		for _, p := range m.Players {
-			if pReset, ok := playerResets[p.PlayerName]; ok {
-				if m.CreatedAt.Before(pReset) {
-					continue
-				}
-			}
-
-			if _, exists := statsMap[p.PlayerID]; !exists {
-				statsMap[p.PlayerID] = &PlayerStats{
-					ID:   p.PlayerID,
-					Name: p.PlayerName,
-				}
-			}
-
-			stat := statsMap[p.PlayerID]
-			stat.Matches++
-			stat.Kills += p.Kills
-			stat.Deaths += p.Deaths
-			stat.Assists += p.Assists
-
-			if strings.EqualFold(p.Result, "WIN") {
-				stat.Wins++
-			} else {
-				stat.Losses++
-			}
-		}
-	}
-
-	var statsList []*PlayerStats
-	for _, st := range statsMap {
-		statsList = append(statsList, st)
+	statsList := make([]*PlayerStats, 0, len(aggregates))
+	for _, a := range aggregates {
+		statsList = append(statsList, statsFromAggregate(game, a, ratings))
 	}
 	return statsList, nil
 }
 
-func generateSignature(m *models.Match) string {
-	var sb strings.Builder
-	for _, p := range m.Players {
-		sb.WriteString(fmt.Sprintf("%s-%s-%d-%d-%d%s", p.PlayerName, p.Result, p.Kills, p.Deaths, p.Assists, signatureSeparator))
-	}
-	return sb.String()
-}
-
 func (s *MatchServiceImpl) SetTimer(dateStr string) error {
 	layout := "2006-01-02"
 	t, err := time.Parse(layout, dateStr)
 	if err != nil {
 		return fmt.Errorf("неверный формат даты, используйте YYYY-MM-DD")
 	}
-	return s.repo.SetSeasonStartDate(t)
-}
-
-func (s *MatchServiceImpl) ResetGlobal() error {
-	return s.repo.SetSeasonStartDate(time.Now())
+	if err := s.repo.SetSeasonStartDate(t); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("сейчас нет активного сезона, начните его командой /season start")
+		}
+		return err
+	}
+	s.refreshAllStats()
+	return nil
 }
 
 func (s *MatchServiceImpl) ResetPlayer(name, dateStr string) error {
@@ -314,11 +654,28 @@ func (s *MatchServiceImpl) ResetPlayer(name, dateStr string) error {
 			return fmt.Errorf("неверный формат даты")
 		}
 	}
-	return s.repo.SetPlayerResetDate(name, t)
+	if err := s.repo.SetPlayerResetDate(name, t); err != nil {
+		return err
+	}
+	s.refreshAllStats()
+	return nil
 }
 
 func (s *MatchServiceImpl) DeleteMatch(id int) error {
-	return s.repo.Delete(id)
+	match, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("матч #%d не найден", id)
+	}
+	if err := s.closedSeasonError(match); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	if match.Status == models.MatchStatusApproved {
+		s.onResultsChanged(match.Game, match)
+	}
+	return nil
 }
 
 func (s *MatchServiceImpl) WipeAllData() error {
@@ -326,18 +683,19 @@ func (s *MatchServiceImpl) WipeAllData() error {
 		return fmt.Errorf("ошибка очистки БД: %w", err)
 	}
 	if s.sheetsClient != nil {
-		headers := [][]interface{}{
-			{"Rank", "ID", "Player", "Matches", "Wins", "Losses", "WinRate %", "KDA"},
+		for _, game := range games.All() {
+			_ = s.writeSheet(game, sheetRows(nil))
 		}
-		_ = s.sheetsClient.ClearRange(s.spreadsheetID, "A1:Z1000")
-		_ = s.sheetsClient.UpdateValues(s.spreadsheetID, "A1", headers)
 	}
-	_ = s.repo.SetSeasonStartDate(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	if _, err := s.RebuildStats(); err != nil {
+		s.logger.Error("failed to rebuild stats: %v", err)
+	}
 	return nil
 }
 
-func (s *MatchServiceImpl) GetExcelReport() ([]byte, error) {
-	statsList, err := s.calculateStats()
+// GetExcelReport exports the leaderboard selected by the query in its order
+func (s *MatchServiceImpl) GetExcelReport(game *games.Profile, q LeaderboardQuery) ([]byte, error) {
+	statsList, err := s.GetLeaderboard(game, q)
 	if err != nil {
 		return nil, err
 	}
@@ -347,7 +705,8 @@ func (s *MatchServiceImpl) GetExcelReport() ([]byte, error) {
 	f.NewSheet(sheet)
 	f.DeleteSheet("Sheet1")
 
-	headers := []string{"ID", "Player", "Matches", "Wins", "Losses", "WinRate %", "KDA"}
+	headers := []string{"ID", "Player", "Matches", "Wins", "Losses", "WinRate %", "KDA", "Rating",
+		"Avg Gold", "Avg Hero Dmg", "Avg Dmg Taken", "Avg Turret Dmg", "Teamfight %", "MVP", "Top Heroes"}
 	for i, h := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
 		f.SetCellValue(sheet, cell, h)
@@ -356,7 +715,6 @@ func (s *MatchServiceImpl) GetExcelReport() ([]byte, error) {
 	row := 2
 	for _, st := range statsList {
 		winRate := calculateWinRate(st.Wins, st.Matches)
-		kdaRatio := calculateKDA(st.Kills, st.Deaths, st.Assists)
 
 		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), st.ID)
 		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), st.Name)
@@ -364,13 +722,22 @@ func (s *MatchServiceImpl) GetExcelReport() ([]byte, error) {
 		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), st.Wins)
 		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), st.Losses)
 		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), fmt.Sprintf("%.1f%%", winRate))
-		f.SetCellValue(sheet, fmt.Sprintf("G%d", row), fmt.Sprintf("%.2f", kdaRatio))
+		f.SetCellValue(sheet, fmt.Sprintf("G%d", row), fmt.Sprintf("%.2f", st.KDA))
+		f.SetCellValue(sheet, fmt.Sprintf("H%d", row), int(math.Round(st.Rating)))
+		f.SetCellValue(sheet, fmt.Sprintf("I%d", row), int(averagePerMatch(st.Gold, st.Matches)))
+		f.SetCellValue(sheet, fmt.Sprintf("J%d", row), int(averagePerMatch(st.HeroDamage, st.Matches)))
+		f.SetCellValue(sheet, fmt.Sprintf("K%d", row), int(averagePerMatch(st.DamageTaken, st.Matches)))
+		f.SetCellValue(sheet, fmt.Sprintf("L%d", row), int(averagePerMatch(st.TurretDamage, st.Matches)))
+		f.SetCellValue(sheet, fmt.Sprintf("M%d", row), fmt.Sprintf("%.1f%%", st.AvgTeamfightPct()))
+		f.SetCellValue(sheet, fmt.Sprintf("N%d", row), st.MVPs)
+		f.SetCellValue(sheet, fmt.Sprintf("O%d", row), strings.Join(st.TopHeroes(topHeroesLimit), ", "))
 		row++
 	}
 
 	f.SetColWidth(sheet, "A", "A", 10)
 	f.SetColWidth(sheet, "B", "B", 20)
-	f.SetColWidth(sheet, "C", "G", 12)
+	f.SetColWidth(sheet, "C", "M", 12)
+	f.SetColWidth(sheet, "N", "N", 30)
 
 	buf, err := f.WriteToBuffer()
 	if err != nil {
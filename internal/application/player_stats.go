@@ -0,0 +1,141 @@
+package application
+
+import (
+	"reflect"
+	"time"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// onResultsChanged refreshes everything derived from the approved matches of a game after a match was counted,
+// changed or removed. Pass the match as it was and as it is now when an edit may move players or the date
+func (s *MatchServiceImpl) onResultsChanged(gameID string, matches ...*models.Match) {
+	game := games.Resolve(gameID)
+	if err := s.refreshPlayerStats(game, playerIDsOf(matches...)); err != nil {
+		s.logger.Error("failed to refresh player stats: %v", err)
+	}
+	if err := s.rebuildRatings(game, earliestDate(matches...)); err != nil {
+		s.logger.Error("failed to rebuild ratings: %v", err)
+	}
+	s.autoSyncSheet()
+}
+
+// refreshAllStats recomputes the stored standings of every player, needed when the season window
+// or a personal reset moves rather than a single match
+func (s *MatchServiceImpl) refreshAllStats() {
+	for _, game := range games.All() {
+		if err := s.refreshPlayerStats(game, nil); err != nil {
+			s.logger.Error("failed to refresh player stats: %v", err)
+		}
+	}
+	s.autoSyncSheet()
+}
+
+// refreshPlayerStats recomputes the stored standings of the given players, of everyone when playerIDs is nil.
+// player_stats hold the current standings only and carry no season: a closed season is an immutable snapshot
+// in season_standings, so its matches cannot be changed (see closedSeasonError)
+func (s *MatchServiceImpl) refreshPlayerStats(game *games.Profile, playerIDs []int) error {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	since, err := s.repo.GetSeasonStartDate()
+	if err != nil {
+		return err
+	}
+	return s.repo.RefreshPlayerStats(game.ID, since, playerIDs)
+}
+
+// RebuildStats recomputes the stored standings and the ratings of every game from the matches and returns
+// how many player standings were out of date, used as a consistency check
+func (s *MatchServiceImpl) RebuildStats() (int, error) {
+	stale := 0
+	for _, game := range games.All() {
+		before, err := s.repo.GetPlayerAggregates(game.ID, 0)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.refreshPlayerStats(game, nil); err != nil {
+			return 0, err
+		}
+		after, err := s.repo.GetPlayerAggregates(game.ID, 0)
+		if err != nil {
+			return 0, err
+		}
+		stale += countStale(before, after)
+	}
+
+	if err := s.RebuildRatings(); err != nil {
+		return stale, err
+	}
+	if stale > 0 {
+		s.logger.Warn("Rebuilt player stats, %d standing(s) were out of date", stale)
+		s.autoSyncSheet()
+	}
+	return stale, nil
+}
+
+// currentRatings returns the ratings of the players of a game, of a single player when playerID is not 0
+func (s *MatchServiceImpl) currentRatings(game *games.Profile, playerID int) (map[int]float64, error) {
+	ratings := make(map[int]float64)
+	if playerID != 0 {
+		changes, err := s.repo.GetRatingHistory(game.ID, playerID, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			ratings[playerID] = changes[0].After
+		}
+		return ratings, nil
+	}
+
+	all, err := s.repo.GetRatings(game.ID)
+	if err != nil {
+		return nil, err
+	}
+	for id, r := range all {
+		ratings[id] = r.Rating
+	}
+	return ratings, nil
+}
+
+// playerIDsOf returns the distinct players bound in the matches
+func playerIDsOf(matches ...*models.Match) []int {
+	seen := make(map[int]bool)
+	ids := make([]int, 0)
+	for _, m := range matches {
+		for _, p := range m.Players {
+			if p.PlayerID != 0 && !seen[p.PlayerID] {
+				seen[p.PlayerID] = true
+				ids = append(ids, p.PlayerID)
+			}
+		}
+	}
+	return ids
+}
+
+func earliestDate(matches ...*models.Match) time.Time {
+	var earliest time.Time
+	for i, m := range matches {
+		if i == 0 || m.Date().Before(earliest) {
+			earliest = m.Date()
+		}
+	}
+	return earliest
+}
+
+func countStale(before, after []models.PlayerAggregate) int {
+	stored := make(map[int]models.PlayerAggregate, len(before))
+	for _, a := range before {
+		stored[a.PlayerID] = a
+	}
+
+	stale := 0
+	for _, a := range after {
+		old, ok := stored[a.PlayerID]
+		if !ok || !reflect.DeepEqual(old, a) {
+			stale++
+		}
+		delete(stored, a.PlayerID)
+	}
+	return stale + len(stored)
+}
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// RebuildRatings replays the rating history of every game from scratch
+func (s *MatchServiceImpl) RebuildRatings() error {
+	for _, game := range games.All() {
+		if err := s.rebuildRatings(game, time.Time{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildRatings replays the approved matches of a game played since the date, starting from the ratings
+// stored before it, so a zero date replays everything. Every later match depends on the changed one,
+// so an edit of an old match still costs a replay of the whole tail: one query for the matches,
+// one for the starting ratings and a copy of the new history rows
+func (s *MatchServiceImpl) rebuildRatings(game *games.Profile, since time.Time) error {
+	s.ratingMu.Lock()
+	defer s.ratingMu.Unlock()
+
+	stored, err := s.repo.GetRatingsBefore(game.ID, since)
+	if err != nil {
+		return err
+	}
+	start := make(map[int]float64, len(stored))
+	for id, r := range stored {
+		start[id] = r.Rating
+	}
+
+	matches, err := s.repo.GetAllAfter(since)
+	if err != nil {
+		return err
+	}
+
+	var played []models.Match
+	for _, m := range matches {
+		if games.Resolve(m.Game) == game {
+			played = append(played, m)
+		}
+	}
+	return s.repo.ReplaceRatingHistory(game.ID, since, replayRatings(played, start))
+}
+
+// replayRatings runs team Elo over the matches: every player of a team moves by the same amount,
+// computed from the average ratings of both teams, and the winners gain what the losers lose.
+// Players missing from start begin at the initial rating
+func replayRatings(matches []models.Match, start map[int]float64) []models.RatingChange {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if !matches[i].Date().Equal(matches[j].Date()) {
+			return matches[i].Date().Before(matches[j].Date())
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	ratings := make(map[int]float64, len(start))
+	for id, r := range start {
+		ratings[id] = r
+	}
+	rating := func(playerID int) float64 {
+		if r, ok := ratings[playerID]; ok {
+			return r
+		}
+		return initialRating
+	}
+
+	var changes []models.RatingChange
+	for _, m := range matches {
+		var winners, losers []int
+		for _, p := range m.Players {
+			if p.PlayerID == 0 {
+				continue
+			}
+			if strings.EqualFold(p.Result, "WIN") {
+				winners = append(winners, p.PlayerID)
+			} else {
+				losers = append(losers, p.PlayerID)
+			}
+		}
+		if len(winners) == 0 || len(losers) == 0 {
+			continue
+		}
+
+		expected := 1 / (1 + math.Pow(10, (teamRating(losers, rating)-teamRating(winners, rating))/400))
+		delta := ratingK * (1 - expected)
+
+		for _, team := range []struct {
+			players []int
+			delta   float64
+		}{{winners, delta}, {losers, -delta}} {
+			for _, id := range team.players {
+				before := rating(id)
+				ratings[id] = before + team.delta
+				changes = append(changes, models.RatingChange{
+					MatchID:  m.ID,
+					PlayerID: id,
+					Before:   before,
+					After:    ratings[id],
+					PlayedAt: m.Date(),
+				})
+			}
+		}
+	}
+	return changes
+}
+
+func teamRating(players []int, rating func(int) float64) float64 {
+	total := 0.0
+	for _, id := range players {
+		total += rating(id)
+	}
+	return total / float64(len(players))
+}
+
+// RestoreMatch brings back a deleted match, its results count again and the ratings are replayed
+func (s *MatchServiceImpl) RestoreMatch(id int) (*models.Match, error) {
+	deleted, err := s.repo.GetDeletedByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("удалённый матч #%d не найден", id)
+	}
+	if err := s.closedSeasonError(deleted); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Restore(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("удалённый матч #%d не найден", id)
+		}
+		return nil, err
+	}
+
+	match, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if match.Status == models.MatchStatusApproved {
+		s.onResultsChanged(match.Game, match)
+	}
+	return match, nil
+}
+
+func (s *MatchServiceImpl) GetRatingHistory(game *games.Profile, playerID int) ([]models.RatingChange, error) {
+	return s.repo.GetRatingHistory(game.ID, playerID, ratingHistoryLimit)
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"math"
+	"testing"
+	"time"
+	"valhalla/internal/models"
+)
+
+// ratingSeries is a run of matches between rotating lobbies, one a day
+func ratingSeries(days int) []models.Match {
+	start := time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC)
+	var matches []models.Match
+	for day := 0; day < days; day++ {
+		m := resolvedLobby()
+		m.ID = day + 1
+		m.CreatedAt = start.AddDate(0, 0, day)
+		for i := range m.Players {
+			// Shift the rosters so ratings spread out instead of moving in lockstep
+			m.Players[i].PlayerID = (m.Players[i].PlayerID+day*3)%14 + 1
+		}
+		matches = append(matches, *m)
+	}
+	return matches
+}
+
+func TestReplayRatingsFromStoredRatings(t *testing.T) {
+	matches := ratingSeries(8)
+	full := replayRatings(append([]models.Match(nil), matches...), nil)
+
+	since := matches[5].Date()
+	start := make(map[int]float64)
+	tail := 0
+	for _, c := range full {
+		if c.PlayedAt.Before(since) {
+			start[c.PlayerID] = c.After
+		} else {
+			tail++
+		}
+	}
+
+	partial := replayRatings(append([]models.Match(nil), matches[5:]...), start)
+	if len(partial) != tail {
+		t.Fatalf("replayed %d changes, want %d", len(partial), tail)
+	}
+	for i, c := range partial {
+		want := full[len(full)-tail+i]
+		if c.MatchID != want.MatchID || c.PlayerID != want.PlayerID ||
+			math.Abs(c.Before-want.Before) > 1e-9 || math.Abs(c.After-want.After) > 1e-9 {
+			t.Errorf("change %d = %+v, want %+v", i, c, want)
+		}
+	}
+}
+
+func TestReplayRatingsZeroSum(t *testing.T) {
+	changes := replayRatings(ratingSeries(5), nil)
+
+	total := 0.0
+	for _, c := range changes {
+		total += c.After - c.Before
+	}
+	if math.Abs(total) > 1e-9 {
+		t.Errorf("rating changes sum to %f, want 0", total)
+	}
+	if changes[0].Before != initialRating {
+		t.Errorf("first rating = %f, want %f", changes[0].Before, initialRating)
+	}
+}
@@ -0,0 +1,254 @@
+package application
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+	"valhalla/pkg/storage"
+)
+
+// ReparseResult pairs a stored match with a fresh parse of its original screenshot
+type ReparseResult struct {
+	Current *models.Match
+	Parsed  *models.Match
+	Changes []string
+}
+
+// ReparseMatch runs the current AI provider on the archived screenshot of a match. The parse is held in memory
+// until ApplyReparse or DiscardReparse and is lost on restart, nothing is written before that.
+// The AI calls count against the requester
+func (s *MatchServiceImpl) ReparseMatch(id int, requester models.MatchSource) (*ReparseResult, error) {
+	match, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("матч #%d не найден", id)
+	}
+	if match.Manual {
+		return nil, fmt.Errorf("матч #%d внесён вручную, скриншота нет", id)
+	}
+	if err := s.closedSeasonError(match); err != nil {
+		return nil, err
+	}
+
+	data, err := s.loadOriginal(match)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := s.parseValidated(data, games.Resolve(match.Game), requester)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolvePlayers(parsed); err != nil {
+		return nil, err
+	}
+
+	s.reparseMu.Lock()
+	s.reparses[id] = parsed
+	s.reparseMu.Unlock()
+
+	return &ReparseResult{Current: match, Parsed: parsed, Changes: diffMatches(match, parsed)}, nil
+}
+
+// ApplyReparse replaces the stored results of a match with its held re-parse, the status is kept.
+// A stored match must not become ambiguous or turn into a copy of another match
+func (s *MatchServiceImpl) ApplyReparse(id int, adminID string) (*models.Match, error) {
+	s.reparseMu.Lock()
+	parsed, ok := s.reparses[id]
+	delete(s.reparses, id)
+	s.reparseMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("нет результата перераспознавания матча #%d, запустите /reparse_match заново", id)
+	}
+
+	match, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("матч #%d не найден", id)
+	}
+
+	// Pending matches resolve names on the review card, a held parse cannot be edited so aliases are the way out
+	if lines := ambiguousNames(parsed); len(lines) > 0 && match.Status != models.MatchStatusPending {
+		return nil, fmt.Errorf("неоднозначные ники, привяжите их командой /alias add и запустите /reparse_match заново:\n%s",
+			strings.Join(lines, "\n"))
+	}
+	parsed.ID = id
+	parsed.MatchSignature = match.MatchSignature
+	if parsed.PlayedAt == nil {
+		parsed.PlayedAt = match.PlayedAt
+	}
+	// The re-parsed date may move the match into a closed season as well
+	moved := *match
+	moved.PlayedAt = parsed.PlayedAt
+	if err := s.closedSeasonError(match, &moved); err != nil {
+		return nil, err
+	}
+	if err := s.checkDuplicate(parsed); err != nil {
+		return nil, err
+	}
+	if err := s.createNewPlayers(parsed); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceParse(id, *parsed); err != nil {
+		return nil, err
+	}
+	s.recordEdit(match, models.MatchEditReparse, adminID, diffMatches(match, parsed))
+
+	s.logger.Info("Match %d re-parsed by %s", id, adminID)
+	updated, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if match.Status == models.MatchStatusApproved {
+		s.onResultsChanged(match.Game, match, updated)
+	}
+	return updated, nil
+}
+
+func (s *MatchServiceImpl) DiscardReparse(id int) {
+	s.reparseMu.Lock()
+	delete(s.reparses, id)
+	s.reparseMu.Unlock()
+}
+
+// loadOriginal returns the scoreboard image a match was parsed from, cropped again for matches cut out of a collage
+func (s *MatchServiceImpl) loadOriginal(match *models.Match) ([]byte, error) {
+	originalHash := match.FileHash
+	if match.SourceHash != "" {
+		originalHash = match.SourceHash
+	}
+
+	data, err := s.loadArchived(match, originalHash)
+	if err != nil {
+		return nil, err
+	}
+
+	regions := s.detectScoreboards(data)
+	if match.RegionIndex < len(regions) {
+		return regions[match.RegionIndex], nil
+	}
+	return data, nil
+}
+
+// loadArchived reads the archived screenshot, falling back to the attachment URL for matches
+// uploaded before archiving was enabled, while the CDN link still works
+func (s *MatchServiceImpl) loadArchived(match *models.Match, originalHash string) ([]byte, error) {
+	if s.blobStore != nil {
+		data, err := s.blobStore.Get(originalHash)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	if match.Source.AttachmentURL == "" {
+		return nil, fmt.Errorf("оригинал скриншота матча #%d не сохранён", match.ID)
+	}
+	data, err := downloadImage(match.Source.AttachmentURL)
+	if err != nil {
+		return nil, fmt.Errorf("оригинал скриншота матча #%d не сохранён, а ссылка недоступна: %w", match.ID, err)
+	}
+
+	hash := sha256.Sum256(data)
+	if hex.EncodeToString(hash[:]) == originalHash {
+		s.archiveImage(originalHash, data)
+	}
+	return data, nil
+}
+
+func (s *MatchServiceImpl) archiveImage(fileHash string, data []byte) {
+	if s.blobStore == nil {
+		return
+	}
+	if err := s.blobStore.Put(fileHash, data); err != nil {
+		s.logger.Warn("failed to archive screenshot %s: %v", fileHash, err)
+	}
+}
+
+// diffMatches describes how a fresh parse differs from the stored match, one line per change.
+// Rows are paired by resolved player, then by name, whatever is left is shown as removed or added
+func diffMatches(current, parsed *models.Match) []string {
+	var changes []string
+	if current.BlueScore != parsed.BlueScore || current.RedScore != parsed.RedScore {
+		changes = append(changes, fmt.Sprintf("Счёт: %d:%d → %d:%d", current.BlueScore, current.RedScore, parsed.BlueScore, parsed.RedScore))
+	}
+	if current.DurationSec != parsed.DurationSec {
+		changes = append(changes, fmt.Sprintf("Длительность: %s → %s", formatDuration(current.DurationSec), formatDuration(parsed.DurationSec)))
+	}
+	if current.GameMode != parsed.GameMode {
+		changes = append(changes, fmt.Sprintf("Режим: %q → %q", current.GameMode, parsed.GameMode))
+	}
+
+	used := make([]bool, len(parsed.Players))
+	for _, old := range current.Players {
+		j := findCounterpart(old, parsed.Players, used)
+		if j < 0 {
+			changes = append(changes, "− "+formatResultLine(old))
+			continue
+		}
+		used[j] = true
+		if diff := diffResult(old, parsed.Players[j]); diff != "" {
+			changes = append(changes, old.PlayerName+": "+diff)
+		}
+	}
+	for j, p := range parsed.Players {
+		if !used[j] {
+			changes = append(changes, "+ "+formatResultLine(p))
+		}
+	}
+	return changes
+}
+
+func findCounterpart(p models.PlayerResult, candidates []models.PlayerResult, used []bool) int {
+	if p.PlayerID != 0 {
+		for j, c := range candidates {
+			if !used[j] && c.PlayerID == p.PlayerID {
+				return j
+			}
+		}
+	}
+	for j, c := range candidates {
+		if !used[j] && strings.EqualFold(c.PlayerName, p.PlayerName) {
+			return j
+		}
+	}
+	return -1
+}
+
+func diffResult(old, parsed models.PlayerResult) string {
+	var parts []string
+	if old.PlayerName != parsed.PlayerName {
+		parts = append(parts, fmt.Sprintf("ник %s → %s", old.PlayerName, parsed.PlayerName))
+	}
+	if old.Result != parsed.Result {
+		parts = append(parts, fmt.Sprintf("%s → %s", old.Result, parsed.Result))
+	}
+	if old.Kills != parsed.Kills || old.Deaths != parsed.Deaths || old.Assists != parsed.Assists {
+		parts = append(parts, fmt.Sprintf("%d/%d/%d → %d/%d/%d", old.Kills, old.Deaths, old.Assists, parsed.Kills, parsed.Deaths, parsed.Assists))
+	}
+	if old.Champion != parsed.Champion {
+		parts = append(parts, fmt.Sprintf("герой %s → %s", valueOrDash(old.Champion), valueOrDash(parsed.Champion)))
+	}
+	if old.Gold != parsed.Gold {
+		parts = append(parts, fmt.Sprintf("золото %d → %d", old.Gold, parsed.Gold))
+	}
+	if old.Medal != parsed.Medal {
+		parts = append(parts, fmt.Sprintf("медаль %s → %s", valueOrDash(old.Medal), valueOrDash(parsed.Medal)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func formatDuration(sec int) string {
+	return fmt.Sprintf("%d:%02d", sec/60, sec%60)
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "—"
+	}
+	return value
+}
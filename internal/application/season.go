@@ -0,0 +1,252 @@
+package application
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// StartSeason begins a new season now. A running season is closed first and its standings are archived.
+// Without a name the season is numbered after the existing ones
+func (s *MatchServiceImpl) StartSeason(name, adminID string) (started, closed *models.Season, err error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		seasons, err := s.repo.GetSeasons()
+		if err != nil {
+			return nil, nil, err
+		}
+		name = fmt.Sprintf("Сезон %d", len(seasons)+1)
+	}
+	if utf8.RuneCountInString(name) > seasonNameMaxLength {
+		return nil, nil, fmt.Errorf("название сезона длиннее %d символов", seasonNameMaxLength)
+	}
+	existing, err := s.repo.GetSeasonByName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil {
+		return nil, nil, fmt.Errorf("сезон %q уже есть", existing.Name)
+	}
+
+	closed, err = s.repo.GetActiveSeason()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	started = &models.Season{Name: name, Status: models.SeasonActive, StartedAt: time.Now(), StartedBy: adminID}
+	if closed == nil {
+		started.ID, err = s.repo.CreateSeason(*started)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		// The running season is closed together with the start, a failed start keeps it running
+		standings, err := s.seasonStandings(closed)
+		if err != nil {
+			return nil, nil, err
+		}
+		started.ID, err = s.repo.StartNextSeason(closed.ID, started.StartedAt, adminID, standings, *started)
+		if err != nil {
+			return nil, nil, seasonCloseError(closed, err)
+		}
+		s.markSeasonClosed(closed, started.StartedAt, adminID, len(standings))
+	}
+
+	s.logger.Info("Season %q started by %s", name, adminID)
+	s.refreshAllStats()
+	return started, closed, nil
+}
+
+// EndSeason closes the running season and archives its standings, until the next start
+// the current standings count the matches played after it
+func (s *MatchServiceImpl) EndSeason(adminID string) (*models.Season, error) {
+	season, err := s.repo.GetActiveSeason()
+	if err != nil {
+		return nil, err
+	}
+	if season == nil {
+		return nil, fmt.Errorf("сейчас нет активного сезона")
+	}
+	if err := s.closeSeason(season, adminID); err != nil {
+		return nil, err
+	}
+
+	s.refreshAllStats()
+	return season, nil
+}
+
+func (s *MatchServiceImpl) GetSeasons() ([]models.Season, error) {
+	return s.repo.GetSeasons()
+}
+
+// closeSeason freezes the standings of every game as they are at closing time
+func (s *MatchServiceImpl) closeSeason(season *models.Season, adminID string) error {
+	standings, err := s.seasonStandings(season)
+	if err != nil {
+		return err
+	}
+
+	endedAt := time.Now()
+	if err := s.repo.CloseSeason(season.ID, endedAt, adminID, standings); err != nil {
+		return seasonCloseError(season, err)
+	}
+	s.markSeasonClosed(season, endedAt, adminID, len(standings))
+	return nil
+}
+
+// seasonStandings ranks the current standings of every game for the archive of the closing season
+func (s *MatchServiceImpl) seasonStandings(season *models.Season) ([]models.SeasonStanding, error) {
+	var standings []models.SeasonStanding
+	for _, game := range games.All() {
+		stats, err := s.calculateStats(game, 0)
+		if err != nil {
+			return nil, err
+		}
+		sortStats(stats, SortMatches)
+		for rank, st := range stats {
+			standings = append(standings, standingFromStats(season.ID, game.ID, rank+1, st))
+		}
+	}
+	return standings, nil
+}
+
+// closedSeasonError rejects changes to approved matches played in a closed season. Its standings are a snapshot
+// frozen at closing time and the stored stats only cover the current standings, so nothing would pick the change up
+func (s *MatchServiceImpl) closedSeasonError(matches ...*models.Match) error {
+	var seasons []models.Season
+	for _, m := range matches {
+		if m.Status != models.MatchStatusApproved {
+			continue
+		}
+		if seasons == nil {
+			var err error
+			if seasons, err = s.repo.GetSeasons(); err != nil {
+				return err
+			}
+		}
+		date := m.Date()
+		for _, season := range seasons {
+			if season.Status != models.SeasonClosed || season.EndedAt == nil {
+				continue
+			}
+			if !date.Before(season.StartedAt) && date.Before(*season.EndedAt) {
+				return fmt.Errorf("матч #%d сыгран в закрытом сезоне %q, его итоги заморожены и не меняются", m.ID, season.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func seasonCloseError(season *models.Season, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("сезон %q уже закрыт", season.Name)
+	}
+	return err
+}
+
+func (s *MatchServiceImpl) markSeasonClosed(season *models.Season, endedAt time.Time, adminID string, archived int) {
+	season.Status = models.SeasonClosed
+	season.EndedAt = &endedAt
+	season.EndedBy = adminID
+	s.logger.Info("Season %q closed by %s, %d standing(s) archived", season.Name, adminID, archived)
+}
+
+// seasonStats returns the standings of a season by name: live for the running season and for an empty name,
+// frozen at closing time for a closed one. A non-zero playerID limits them to that player
+func (s *MatchServiceImpl) seasonStats(game *games.Profile, seasonName string, playerID int) ([]*PlayerStats, error) {
+	if seasonName == "" {
+		return s.calculateStats(game, playerID)
+	}
+
+	season, err := s.findSeason(seasonName)
+	if err != nil {
+		return nil, err
+	}
+	if season.Status == models.SeasonActive {
+		return s.calculateStats(game, playerID)
+	}
+
+	standings, err := s.repo.GetSeasonStandings(season.ID, game.ID)
+	if err != nil {
+		return nil, err
+	}
+	statsList := make([]*PlayerStats, 0, len(standings))
+	for _, st := range standings {
+		if playerID == 0 || st.PlayerID == playerID {
+			statsList = append(statsList, statsFromStanding(st))
+		}
+	}
+	return statsList, nil
+}
+
+// findSeason looks a season up by name, an empty name means no particular season and gives nil
+func (s *MatchServiceImpl) findSeason(name string) (*models.Season, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	season, err := s.repo.GetSeasonByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if season == nil {
+		return nil, fmt.Errorf("сезон %q не найден", name)
+	}
+	return season, nil
+}
+
+func standingFromStats(seasonID int, gameID string, rank int, st *PlayerStats) models.SeasonStanding {
+	return models.SeasonStanding{
+		SeasonID:     seasonID,
+		Game:         gameID,
+		PlayerID:     st.ID,
+		PlayerName:   st.Name,
+		Rank:         rank,
+		Matches:      st.Matches,
+		Wins:         st.Wins,
+		Losses:       st.Losses,
+		Kills:        st.Kills,
+		Deaths:       st.Deaths,
+		Assists:      st.Assists,
+		KDA:          st.KDA,
+		Rating:       st.Rating,
+		Gold:         st.Gold,
+		HeroDamage:   st.HeroDamage,
+		DamageTaken:  st.DamageTaken,
+		TurretDamage: st.TurretDamage,
+		TeamfightPct: st.TeamfightPct,
+		MVPs:         st.MVPs,
+		Heroes:       st.Heroes,
+	}
+}
+
+func statsFromStanding(st models.SeasonStanding) *PlayerStats {
+	heroes := st.Heroes
+	if heroes == nil {
+		heroes = make(map[string]int)
+	}
+	return &PlayerStats{
+		ID:           st.PlayerID,
+		Name:         st.PlayerName,
+		Matches:      st.Matches,
+		Wins:         st.Wins,
+		Losses:       st.Losses,
+		Kills:        st.Kills,
+		Deaths:       st.Deaths,
+		Assists:      st.Assists,
+		KDA:          st.KDA,
+		Rating:       st.Rating,
+		Gold:         st.Gold,
+		HeroDamage:   st.HeroDamage,
+		DamageTaken:  st.DamageTaken,
+		TurretDamage: st.TurretDamage,
+		TeamfightPct: st.TeamfightPct,
+		MVPs:         st.MVPs,
+		Heroes:       heroes,
+	}
+}
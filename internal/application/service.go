@@ -1,13 +1,17 @@
 package application
 
 import (
+	"valhalla/internal/games"
 	"valhalla/internal/models"
 	"valhalla/internal/repository"
 	"valhalla/pkg/sheets"
+	"valhalla/pkg/storage"
 )
 
+// AIProvider reads scoreboards. Token usage is returned with failed parses too when the model answered
 type AIProvider interface {
-	ParseImage(data []byte) (*models.Match, error)
+	ParseImage(data []byte, game *games.Profile) (*models.Match, models.TokenUsage, error)
+	ReparseImage(data []byte, game *games.Profile, violations []string) (*models.Match, models.TokenUsage, error)
 }
 
 type Logger interface {
@@ -18,36 +22,76 @@ type Logger interface {
 }
 
 type MatchService interface {
-	ProcessImage(data []byte) (int, error)
-	ProcessImageFromURL(url string) (int, error)
-	GetExcelReport() ([]byte, error)
+	ProcessImage(data []byte, source models.MatchSource) ([]*models.Match, error)
+	ProcessImageFromURL(url string, source models.MatchSource) ([]*models.Match, error)
+
+	GetPendingMatches() ([]models.Match, error)
+	GetMatch(id int) (*models.Match, error)
+	ApproveMatch(id int, reviewerID string) error
+	RejectMatch(id int, reviewerID string) error
+	UpdatePendingMatch(id int, table string) (*models.Match, error)
+	EditMatch(id int, table, adminID string) (*models.Match, []string, error)
+	GetMatchEdits(id int) ([]models.MatchEdit, error)
+	AddManualMatch(game *games.Profile, table, dateStr string, source models.MatchSource) (*models.Match, error)
+	ResolvePlayer(matchID, resultID, playerID int, adminID string) (*models.Match, error)
+	GetSubmitterStats() ([]models.SubmitterStats, error)
+	GetMatchesBySubmitter(platform, submitterID string) ([]models.Match, error)
+
+	ReparseMatch(id int, requester models.MatchSource) (*ReparseResult, error)
+	ApplyReparse(id int, adminID string) (*models.Match, error)
+	DiscardReparse(id int)
+
+	GetGame(guildID, channelID string) *games.Profile
+	GetGameSelection(guildID, channelID string) (*models.GameSelection, error)
+	SetGame(scope, scopeID, gameID, adminID string) (*games.Profile, error)
+	ResetGame(scope, scopeID string) error
+
+	GetExcelReport(game *games.Profile, q LeaderboardQuery) ([]byte, error)
 	SyncToGoogleSheet() (string, error)
 	SetTimer(dateStr string) error
-	ResetGlobal() error
+	StartSeason(name, adminID string) (started, closed *models.Season, err error)
+	EndSeason(adminID string) (*models.Season, error)
+	GetSeasons() ([]models.Season, error)
 	ResetPlayer(name, dateStr string) error
 	DeleteMatch(id int) error
+	RestoreMatch(id int) (*models.Match, error)
 	WipeAllData() error
 	RenamePlayer(id int, newName string) error
 
-	GetLeaderboard(sortBy string) ([]*PlayerStats, error)
+	GetLeaderboard(game *games.Profile, q LeaderboardQuery) ([]*PlayerStats, error)
+	GetRatingHistory(game *games.Profile, playerID int) ([]models.RatingChange, error)
+	RebuildStats() (int, error)
 
 	GetPlayerList() ([]models.Player, error)
 	GetPlayerNameByID(id int) (string, error)
 	GetHistoryByID(id int) ([]string, error)
 	WipePlayerByID(id int) error
-	GetPlayerStats(name string) (*PlayerStats, error)
-	GetPlayerStatsByID(id int) (*PlayerStats, error)
+	GetPlayerStats(game *games.Profile, name string) (*PlayerStats, error)
+	GetPlayerStatsByID(game *games.Profile, season string, id int) (*PlayerStats, error)
+	GetVersus(game *games.Profile, season string, a, b int) (*PairRecord, error)
+	GetDuo(game *games.Profile, season string, a, b int) (*PairRecord, error)
+	GetTeammates(game *games.Profile, season string, playerID int) (best, worst []PairRecord, err error)
+
+	AddAlias(alias string, playerID int, adminID string) error
+	RemoveAlias(alias string) error
+	GetAliases(playerID int) ([]models.PlayerAlias, error)
 }
 
 type Service struct {
 	MatchService       MatchService
+	IngestionService   IngestionService
+	AIUsageService     AIUsageService
 	ProfileLinkService ProfileLinkService
 	TelegramService    TelegramService
 }
 
-func NewService(repos *repository.Repository, ai AIProvider, sheetsClient sheets.Client, ownerEmail string, logger Logger) *Service {
+func NewService(repos *repository.Repository, ai AIProvider, budget AIBudget, sheetsClient sheets.Client, blobStore storage.BlobStore, policy ReviewPolicy, leaderboard LeaderboardConfig, defaultGame *games.Profile, ingestion IngestionConfig, ownerEmail string, logger Logger) *Service {
+	usageService := NewAIUsageServiceImpl(repos.AIUsage, budget, logger)
+	matchService := NewMatchServiceImpl(repos.Match, ai, usageService, sheetsClient, blobStore, policy, leaderboard, defaultGame, ownerEmail, logger)
 	return &Service{
-		MatchService:       NewMatchServiceImpl(repos.Match, ai, sheetsClient, ownerEmail, logger),
+		MatchService:       matchService,
+		IngestionService:   NewIngestionServiceImpl(repos.Ingestion, matchService, ingestion, logger),
+		AIUsageService:     usageService,
 		ProfileLinkService: NewProfileLinkServiceImpl(repos.ProfileLink, repos.Match, logger),
 		TelegramService:    NewTelegramServiceImpl(repos.Telegram, logger),
 	}
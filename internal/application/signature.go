@@ -0,0 +1,192 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"valhalla/internal/models"
+)
+
+var ErrDuplicateMatch = errors.New("duplicate match detected")
+
+// DuplicateMatchError reports that the parsed match is already stored as MatchID
+type DuplicateMatchError struct {
+	MatchID int
+}
+
+func (e *DuplicateMatchError) Error() string {
+	return fmt.Sprintf("%s: match #%d", ErrDuplicateMatch, e.MatchID)
+}
+
+func (e *DuplicateMatchError) Unwrap() error {
+	return ErrDuplicateMatch
+}
+
+// resolvePlayers binds every result without a player ID to an existing player.
+// Names close to several players, or to one player but not close enough, are left with their candidates
+// for a reviewer to pick from. Names no player is close to stay unbound until createNewPlayers, so that
+// a match rejected as a duplicate does not leave new players behind
+func (s *MatchServiceImpl) resolvePlayers(m *models.Match) error {
+	for i := range m.Players {
+		p := &m.Players[i]
+		if p.PlayerID != 0 || p.Unresolved() {
+			continue
+		}
+		id, candidates, err := s.repo.ResolvePlayerName(p.PlayerName)
+		if err != nil {
+			return fmt.Errorf("failed to resolve player %q: %w", p.PlayerName, err)
+		}
+		p.PlayerID = id
+		p.Candidates = candidates
+	}
+	return nil
+}
+
+// createNewPlayers creates the players resolvePlayers found no match for and refreshes the signature
+func (s *MatchServiceImpl) createNewPlayers(m *models.Match) error {
+	for i := range m.Players {
+		p := &m.Players[i]
+		if p.PlayerID != 0 || p.Unresolved() {
+			continue
+		}
+		id, err := s.repo.CreatePlayer(p.PlayerName)
+		if err != nil {
+			return fmt.Errorf("failed to create player %q: %w", p.PlayerName, err)
+		}
+		p.PlayerID = id
+	}
+	m.MatchSignature = generateSignature(m)
+	return nil
+}
+
+func countUnresolved(m *models.Match) int {
+	count := 0
+	for i := range m.Players {
+		if m.Players[i].Unresolved() {
+			count++
+		}
+	}
+	return count
+}
+
+// checkDuplicate sets the canonical signature and rejects matches already stored,
+// either with the same signature or with nearly the same rosters and KDA lines.
+// A stored match being edited keeps its ID and current signature and is not compared with itself
+func (s *MatchServiceImpl) checkDuplicate(m *models.Match) error {
+	signature := generateSignature(m)
+	if signature != m.MatchSignature {
+		matchID, err := s.repo.FindBySignature(signature)
+		if err != nil {
+			return err
+		}
+		if matchID != 0 {
+			return &DuplicateMatchError{MatchID: matchID}
+		}
+	}
+	m.MatchSignature = signature
+
+	recent, err := s.repo.GetRecentMatches(time.Now().Add(-duplicateSearchWindow))
+	if err != nil {
+		return err
+	}
+	for i := range recent {
+		if recent[i].ID != m.ID && isSameMatch(m, &recent[i]) {
+			return &DuplicateMatchError{MatchID: recent[i].ID}
+		}
+	}
+	return nil
+}
+
+// generateSignature builds an order-insensitive signature from resolved player IDs:
+// each team is sorted by player ID, so the order the AI listed players in does not matter
+func generateSignature(m *models.Match) string {
+	var sb strings.Builder
+	for _, result := range []string{"WIN", "LOSE"} {
+		team := teamResults(m, result)
+		sort.Slice(team, func(i, j int) bool {
+			return team[i].PlayerID < team[j].PlayerID
+		})
+
+		sb.WriteString(result + ":")
+		for _, p := range team {
+			sb.WriteString(fmt.Sprintf("%d-%d-%d-%d%s", p.PlayerID, p.Kills, p.Deaths, p.Assists, signatureSeparator))
+		}
+	}
+	return sb.String()
+}
+
+// isSameMatch reports whether two parses describe the same game even if OCR misread a name or a digit:
+// almost every KDA line must have a close counterpart on the same side and at least half the players must match
+func isSameMatch(a, b *models.Match) bool {
+	if len(a.Players) == 0 || len(a.Players) != len(b.Players) {
+		return false
+	}
+
+	matchedKDA, matchedIDs := 0, 0
+	for _, result := range []string{"WIN", "LOSE"} {
+		teamA, teamB := teamResults(a, result), teamResults(b, result)
+		matchedKDA += countCloseKDA(teamA, teamB)
+		matchedIDs += countSharedPlayers(teamA, teamB)
+	}
+
+	total := len(a.Players)
+	return matchedKDA >= total-maxKDAMismatches && matchedIDs*2 >= total
+}
+
+// countCloseKDA greedily pairs KDA lines whose summed absolute difference is within kdaTolerance
+func countCloseKDA(a, b []models.PlayerResult) int {
+	used := make([]bool, len(b))
+	matched := 0
+	for _, pa := range a {
+		for j, pb := range b {
+			if used[j] {
+				continue
+			}
+			if kdaDistance(pa, pb) <= kdaTolerance {
+				used[j] = true
+				matched++
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func countSharedPlayers(a, b []models.PlayerResult) int {
+	ids := make(map[int]struct{}, len(a))
+	for _, p := range a {
+		if p.PlayerID != 0 {
+			ids[p.PlayerID] = struct{}{}
+		}
+	}
+	shared := 0
+	for _, p := range b {
+		if _, ok := ids[p.PlayerID]; ok {
+			shared++
+		}
+	}
+	return shared
+}
+
+func kdaDistance(a, b models.PlayerResult) int {
+	return abs(a.Kills-b.Kills) + abs(a.Deaths-b.Deaths) + abs(a.Assists-b.Assists)
+}
+
+func teamResults(m *models.Match, result string) []models.PlayerResult {
+	var team []models.PlayerResult
+	for _, p := range m.Players {
+		if strings.EqualFold(p.Result, result) {
+			team = append(team, p)
+		}
+	}
+	return team
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
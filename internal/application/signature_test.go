@@ -0,0 +1,153 @@
+package application
+
+import (
+	"testing"
+	"valhalla/internal/models"
+)
+
+// resolvedLobby is a 5v5 match with players 1-5 winning against players 6-10
+func resolvedLobby() *models.Match {
+	m := &models.Match{}
+	for id := 1; id <= 10; id++ {
+		p := models.PlayerResult{PlayerID: id, Result: "WIN", Kills: id, Deaths: 10 - id, Assists: id * 2}
+		if id > 5 {
+			p.Result = "LOSE"
+		}
+		m.Players = append(m.Players, p)
+	}
+	return m
+}
+
+func TestGenerateSignature(t *testing.T) {
+	base := generateSignature(resolvedLobby())
+
+	tests := []struct {
+		name   string
+		modify func(m *models.Match)
+		same   bool
+	}{
+		{
+			name: "players listed in another order",
+			modify: func(m *models.Match) {
+				for i, j := 0, len(m.Players)-1; i < j; i, j = i+1, j-1 {
+					m.Players[i], m.Players[j] = m.Players[j], m.Players[i]
+				}
+			},
+			same: true,
+		},
+		{
+			name:   "names are not part of the signature",
+			modify: func(m *models.Match) { m.Players[0].PlayerName = "Sh4dow" },
+			same:   true,
+		},
+		{
+			name:   "lowercase result",
+			modify: func(m *models.Match) { m.Players[0].Result = "win" },
+			same:   true,
+		},
+		{
+			name:   "different kills",
+			modify: func(m *models.Match) { m.Players[3].Kills++ },
+		},
+		{
+			name:   "different player",
+			modify: func(m *models.Match) { m.Players[3].PlayerID = 42 },
+		},
+		{
+			name:   "player moved to the other team",
+			modify: func(m *models.Match) { m.Players[0].Result, m.Players[9].Result = "LOSE", "WIN" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := resolvedLobby()
+			tt.modify(m)
+			if got := generateSignature(m); (got == base) != tt.same {
+				t.Errorf("signature %q, base %q, want same = %v", got, base, tt.same)
+			}
+		})
+	}
+}
+
+func TestIsSameMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *models.Match)
+		want   bool
+	}{
+		{
+			name:   "identical",
+			modify: func(m *models.Match) {},
+			want:   true,
+		},
+		{
+			name:   "one digit misread",
+			modify: func(m *models.Match) { m.Players[2].Assists++ },
+			want:   true,
+		},
+		{
+			name:   "one line far off",
+			modify: func(m *models.Match) { m.Players[2].Kills += 5 },
+			want:   true,
+		},
+		{
+			name: "two lines far off",
+			modify: func(m *models.Match) {
+				m.Players[2].Kills += 5
+				m.Players[7].Deaths += 5
+			},
+		},
+		{
+			name: "half the names misread into other players",
+			modify: func(m *models.Match) {
+				for _, i := range []int{0, 1, 5, 6, 7} {
+					m.Players[i].PlayerID += 100
+				}
+			},
+			want: true,
+		},
+		{
+			name: "most names belong to other players",
+			modify: func(m *models.Match) {
+				for _, i := range []int{0, 1, 2, 5, 6, 7} {
+					m.Players[i].PlayerID += 100
+				}
+			},
+		},
+		{
+			name:   "sides swapped",
+			modify: func(m *models.Match) { swapResults(m) },
+		},
+		{
+			name:   "different lobby size",
+			modify: func(m *models.Match) { m.Players = m.Players[:8] },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := resolvedLobby()
+			tt.modify(m)
+			if got := isSameMatch(m, resolvedLobby()); got != tt.want {
+				t.Errorf("isSameMatch = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		if isSameMatch(&models.Match{}, &models.Match{}) {
+			t.Error("empty matches must not be the same")
+		}
+	})
+}
+
+func swapResults(m *models.Match) {
+	for i := range m.Players {
+		if m.Players[i].Result == "WIN" {
+			m.Players[i].Result = "LOSE"
+		} else {
+			m.Players[i].Result = "WIN"
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// PairRecord is the record of a player in the matches shared with another one, seen from the first player
+type PairRecord struct {
+	PlayerID  int
+	Player    string
+	OtherID   int
+	Other     string
+	Matches   int
+	Wins      int
+	Losses    int
+	RecentIDs []int  // newest first
+	RecentWon []bool // outcome of the recent matches for the first player
+}
+
+func (r *PairRecord) WinRate() float64 {
+	return calculateWinRate(r.Wins, r.Matches)
+}
+
+// GetVersus returns how player a did against player b in the season's matches where they were on opposite teams
+func (s *MatchServiceImpl) GetVersus(game *games.Profile, season string, a, b int) (*PairRecord, error) {
+	return s.pairRecord(game, season, a, b, false)
+}
+
+// GetDuo returns the record of players a and b in the season's matches they played on the same team
+func (s *MatchServiceImpl) GetDuo(game *games.Profile, season string, a, b int) (*PairRecord, error) {
+	return s.pairRecord(game, season, a, b, true)
+}
+
+// GetTeammates ranks the teammates of a player by the win rate of their shared matches. Only teammates with
+// enough shared matches are ranked, best and worst do not overlap
+func (s *MatchServiceImpl) GetTeammates(game *games.Profile, season string, playerID int) (best, worst []PairRecord, err error) {
+	matches, err := s.playerMatches(game, season, playerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make(map[int]*PairRecord)
+	for _, m := range matches {
+		self, ok := findResult(m, playerID)
+		if !ok {
+			continue
+		}
+		for _, p := range m.Players {
+			if p.PlayerID == 0 || p.PlayerID == playerID || !sameTeam(self, p) {
+				continue
+			}
+			if _, ok := records[p.PlayerID]; !ok {
+				records[p.PlayerID] = &PairRecord{PlayerID: playerID, Player: self.PlayerName, OtherID: p.PlayerID, Other: p.PlayerName}
+			}
+			records[p.PlayerID].add(m.ID, isWin(self))
+		}
+	}
+
+	var ranked []PairRecord
+	for _, r := range records {
+		if r.Matches >= minTeammateGames {
+			ranked = append(ranked, *r)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].WinRate() != ranked[j].WinRate() {
+			return ranked[i].WinRate() > ranked[j].WinRate()
+		}
+		if ranked[i].Matches != ranked[j].Matches {
+			return ranked[i].Matches > ranked[j].Matches
+		}
+		return ranked[i].OtherID < ranked[j].OtherID
+	})
+
+	bestCount := teammatesLimit
+	if len(ranked) < bestCount {
+		bestCount = len(ranked)
+	}
+	best = ranked[:bestCount]
+	for i := len(ranked) - 1; i >= bestCount && len(worst) < teammatesLimit; i-- {
+		worst = append(worst, ranked[i])
+	}
+	return best, worst, nil
+}
+
+// pairRecord walks the matches of a, newest first, and counts the ones b played on the same or the other team
+func (s *MatchServiceImpl) pairRecord(game *games.Profile, season string, a, b int, together bool) (*PairRecord, error) {
+	if a == b {
+		return nil, fmt.Errorf("выберите двух разных игроков")
+	}
+	nameA, err := s.repo.GetPlayerNameByID(a)
+	if err != nil {
+		return nil, fmt.Errorf("игрок с ID %d не найден", a)
+	}
+	nameB, err := s.repo.GetPlayerNameByID(b)
+	if err != nil {
+		return nil, fmt.Errorf("игрок с ID %d не найден", b)
+	}
+
+	matches, err := s.playerMatches(game, season, a)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &PairRecord{PlayerID: a, Player: nameA, OtherID: b, Other: nameB}
+	for _, m := range matches {
+		self, okA := findResult(m, a)
+		other, okB := findResult(m, b)
+		if !okA || !okB || sameTeam(self, other) != together {
+			continue
+		}
+		record.add(m.ID, isWin(self))
+	}
+	return record, nil
+}
+
+// playerMatches returns the matches of a player in a season, newest first
+func (s *MatchServiceImpl) playerMatches(game *games.Profile, season string, playerID int) ([]models.Match, error) {
+	from, to, err := s.seasonWindow(season)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.repo.GetPlayerMatches(game.ID, playerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].Date().Equal(matches[j].Date()) {
+			return matches[i].Date().After(matches[j].Date())
+		}
+		return matches[i].ID > matches[j].ID
+	})
+	return matches, nil
+}
+
+// seasonWindow returns the period of a season by name, the current standings period when the name is empty
+func (s *MatchServiceImpl) seasonWindow(name string) (from, to time.Time, err error) {
+	season, err := s.findSeason(name)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if season == nil || season.Status == models.SeasonActive {
+		from, err = s.repo.GetSeasonStartDate()
+		return from, time.Time{}, err
+	}
+	return season.StartedAt, *season.EndedAt, nil
+}
+
+func (r *PairRecord) add(matchID int, won bool) {
+	r.Matches++
+	if won {
+		r.Wins++
+	} else {
+		r.Losses++
+	}
+	if len(r.RecentIDs) < pairRecentMatches {
+		r.RecentIDs = append(r.RecentIDs, matchID)
+		r.RecentWon = append(r.RecentWon, won)
+	}
+}
+
+func findResult(m models.Match, playerID int) (models.PlayerResult, bool) {
+	for _, p := range m.Players {
+		if p.PlayerID == playerID {
+			return p, true
+		}
+	}
+	return models.PlayerResult{}, false
+}
+
+// sameTeam compares outcomes rather than the team field, older matches were stored without a side
+func sameTeam(a, b models.PlayerResult) bool {
+	return isWin(a) == isWin(b)
+}
+
+func isWin(p models.PlayerResult) bool {
+	return strings.EqualFold(p.Result, "WIN")
+}
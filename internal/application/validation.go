@@ -0,0 +1,128 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// MatchValidationError is returned when the AI output still breaks scoreboard rules after all retries
+type MatchValidationError struct {
+	Violations []string
+}
+
+func (e *MatchValidationError) Error() string {
+	return "распознавание не прошло проверку: " + strings.Join(e.Violations, "; ")
+}
+
+// validateMatch checks the parsed scoreboard against the game's shape and limits and returns human readable violations
+func validateMatch(m *models.Match, game *games.Profile) []string {
+	var violations []string
+
+	if len(m.Players) != game.PlayersPerMatch() {
+		violations = append(violations, fmt.Sprintf("expected %d players, got %d", game.PlayersPerMatch(), len(m.Players)))
+	}
+
+	wins, losses := 0, 0
+	seen := make(map[string]int)
+	for i, p := range m.Players {
+		label := fmt.Sprintf("player #%d (%q)", i+1, p.PlayerName)
+
+		name := strings.ToLower(strings.TrimSpace(p.PlayerName))
+		if name == "" {
+			violations = append(violations, fmt.Sprintf("player #%d has an empty name", i+1))
+		} else if prev, ok := seen[name]; ok {
+			violations = append(violations, fmt.Sprintf("%s has the same name as player #%d, names must be unique", label, prev+1))
+		} else {
+			seen[name] = i
+		}
+
+		switch p.Result {
+		case "WIN":
+			wins++
+		case "LOSE":
+			losses++
+		default:
+			violations = append(violations, fmt.Sprintf("%s has result %q, must be WIN or LOSE", label, p.Result))
+		}
+
+		if p.Kills < 0 || p.Kills > game.MaxKills {
+			violations = append(violations, fmt.Sprintf("%s has %d kills, expected 0-%d", label, p.Kills, game.MaxKills))
+		}
+		if p.Deaths < 0 || p.Deaths > game.MaxDeaths {
+			violations = append(violations, fmt.Sprintf("%s has %d deaths, expected 0-%d", label, p.Deaths, game.MaxDeaths))
+		}
+		if p.Assists < 0 || p.Assists > game.MaxAssists {
+			violations = append(violations, fmt.Sprintf("%s has %d assists, expected 0-%d", label, p.Assists, game.MaxAssists))
+		}
+	}
+
+	violations = append(violations, validateTeamSides(m, game.PlayersPerTeam)...)
+
+	if wins != game.PlayersPerTeam || losses != game.PlayersPerTeam {
+		violations = append(violations, fmt.Sprintf("expected %d WIN and %d LOSE players, got %d WIN and %d LOSE",
+			game.PlayersPerTeam, game.PlayersPerTeam, wins, losses))
+	}
+
+	return violations
+}
+
+// validateTeamSides checks that sides, when present, split the lobby evenly and each side has a single result
+func validateTeamSides(m *models.Match, playersPerTeam int) []string {
+	sideResults := make(map[string]map[string]int)
+	sideCounts := make(map[string]int)
+	for _, p := range m.Players {
+		if p.Team == "" {
+			continue
+		}
+		if sideResults[p.Team] == nil {
+			sideResults[p.Team] = make(map[string]int)
+		}
+		sideResults[p.Team][p.Result]++
+		sideCounts[p.Team]++
+	}
+	if len(sideCounts) == 0 {
+		return nil
+	}
+
+	var violations []string
+	for _, side := range []string{models.TeamBlue, models.TeamRed} {
+		if sideCounts[side] != playersPerTeam {
+			violations = append(violations, fmt.Sprintf("expected %d players on the %s team, got %d", playersPerTeam, side, sideCounts[side]))
+		}
+		if len(sideResults[side]) > 1 {
+			violations = append(violations, fmt.Sprintf("players of the %s team have different results, the whole team must be WIN or LOSE", side))
+		}
+	}
+	return violations
+}
+
+// parseValidated asks the AI to parse the image and re-prompts it with the violations until the output is valid.
+// Every call is metered and counted against the budgets of the requester
+func (s *MatchServiceImpl) parseValidated(data []byte, game *games.Profile, requester models.MatchSource) (*models.Match, error) {
+	match, err := s.usage.call(requester, models.AIOperationParse, game, func() (*models.Match, models.TokenUsage, error) {
+		return s.ai.ParseImage(data, game)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		violations := validateMatch(match, game)
+		if len(violations) == 0 {
+			return match, nil
+		}
+		if attempt > maxParseRetries {
+			return nil, &MatchValidationError{Violations: violations}
+		}
+
+		s.logger.Warn("AI output failed validation (attempt %d): %v", attempt, violations)
+		match, err = s.usage.call(requester, models.AIOperationReparse, game, func() (*models.Match, models.TokenUsage, error) {
+			return s.ai.ReparseImage(data, game, violations)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+)
+
+// validLobby builds a 5v5 match that passes validation, the winners are on the blue side
+func validLobby() *models.Match {
+	m := &models.Match{}
+	for i := 0; i < 10; i++ {
+		p := models.PlayerResult{PlayerName: fmt.Sprintf("player%d", i), Result: "WIN", Team: models.TeamBlue, Kills: i, Deaths: 2, Assists: 3}
+		if i >= 5 {
+			p.Result, p.Team = "LOSE", models.TeamRed
+		}
+		m.Players = append(m.Players, p)
+	}
+	return m
+}
+
+func TestValidateMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *models.Match)
+		want   []string // substrings of the expected violations, none for a valid match
+	}{
+		{
+			name:   "valid",
+			modify: func(m *models.Match) {},
+		},
+		{
+			name:   "sides are optional",
+			modify: func(m *models.Match) { clearTeams(m) },
+		},
+		{
+			name:   "missing player",
+			modify: func(m *models.Match) { m.Players = m.Players[:9] },
+			want:   []string{"expected 10 players, got 9", "expected 5 players on the red team, got 4", "got 5 WIN and 4 LOSE"},
+		},
+		{
+			name:   "duplicate name ignores case",
+			modify: func(m *models.Match) { m.Players[7].PlayerName = "PLAYER2" },
+			want:   []string{"same name as player #3"},
+		},
+		{
+			name:   "empty name",
+			modify: func(m *models.Match) { m.Players[0].PlayerName = "  " },
+			want:   []string{"player #1 has an empty name"},
+		},
+		{
+			name:   "unknown result",
+			modify: func(m *models.Match) { clearTeams(m); m.Players[4].Result = "DRAW" },
+			want:   []string{`result "DRAW"`, "got 4 WIN and 5 LOSE"},
+		},
+		{
+			name:   "kills above the game limit",
+			modify: func(m *models.Match) { m.Players[1].Kills = 61 },
+			want:   []string{"61 kills, expected 0-60"},
+		},
+		{
+			name:   "negative deaths",
+			modify: func(m *models.Match) { m.Players[1].Deaths = -1 },
+			want:   []string{"-1 deaths"},
+		},
+		{
+			name:   "side with mixed results",
+			modify: func(m *models.Match) { m.Players[0].Result, m.Players[5].Result = "LOSE", "WIN" },
+			want:   []string{"blue team have different results", "red team have different results"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validLobby()
+			tt.modify(m)
+
+			got := validateMatch(m, games.MLBB)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d violations %q, want %d", len(got), got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func clearTeams(m *models.Match) {
+	for i := range m.Players {
+		m.Players[i].Team = ""
+	}
+}
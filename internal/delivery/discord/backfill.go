@@ -0,0 +1,123 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+	"valhalla/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleBackfill queues the screenshots posted to a channel since a date, e.g. while the bot was offline.
+// They go through the same queue as live uploads, already stored screenshots are skipped as duplicates
+func (b *Bot) handleBackfill(s *discordgo.Session, i *discordgo.Interaction) {
+	var channel *discordgo.Channel
+	var sinceStr string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "channel":
+			channel = opt.ChannelValue(s)
+		case "since":
+			sinceStr = opt.StringValue()
+		}
+	}
+
+	since, err := time.ParseInLocation("2006-01-02", sinceStr, time.Local)
+	if err != nil {
+		b.respondMessage(s, i, "Неверный формат даты, используйте YYYY-MM-DD.", true)
+		return
+	}
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	reply := func(text string) {
+		s.InteractionResponseEdit(i, &discordgo.WebhookEdit{Content: &text})
+	}
+
+	messages, scanned, err := b.collectScreenshotMessages(channel.ID, since)
+	if err != nil {
+		b.logger.Error("failed to read history of channel %s: %v", channel.ID, err)
+		reply("❌ Не удалось прочитать историю канала: " + err.Error())
+		return
+	}
+
+	total := 0
+	for _, m := range messages {
+		total += len(screenshotAttachments(m))
+	}
+	if total == 0 {
+		reply(fmt.Sprintf("В <#%s> с %s скриншотов не найдено (просмотрено сообщений: %d).",
+			channel.ID, since.Format("02.01.2006"), scanned))
+		return
+	}
+
+	progress, err := s.ChannelMessageSend(i.ChannelID, fmt.Sprintf("⏳ Загрузка истории <#%s> с %s: %d скриншот(ов) из %d сообщений...",
+		channel.ID, since.Format("02.01.2006"), total, len(messages)))
+	if err != nil {
+		b.logger.Error("failed to send backfill progress message: %v", err)
+		reply("❌ Не удалось отправить сообщение с прогрессом.")
+		return
+	}
+
+	jobs := make([]models.IngestionJob, 0, total)
+	for _, m := range messages {
+		for _, att := range screenshotAttachments(m) {
+			jobs = append(jobs, screenshotJob(m, i.GuildID, att, len(jobs)+1, i.ChannelID, progress.ID))
+		}
+	}
+	if err := b.services.IngestionService.Enqueue(jobs); err != nil {
+		b.logger.Error("failed to enqueue backfill: %v", err)
+		s.ChannelMessageEdit(i.ChannelID, progress.ID, "❌ Не удалось поставить скриншоты в очередь, запустите /backfill ещё раз.")
+		reply("❌ Ошибка очереди: " + err.Error())
+		return
+	}
+
+	b.logger.Info("Backfill of channel %s since %s by %s: %d screenshot(s) queued",
+		channel.ID, since.Format("2006-01-02"), interactionUser(i).ID, total)
+	reply(fmt.Sprintf("✅ В очередь поставлено %d скриншот(ов) из <#%s>, прогресс — в сообщении в этом канале.", total, channel.ID))
+}
+
+// collectScreenshotMessages pages through the channel history from the newest message back to since and
+// returns the user messages with screenshots, oldest first, and how many messages were looked at
+func (b *Bot) collectScreenshotMessages(channelID string, since time.Time) ([]*discordgo.Message, int, error) {
+	var found []*discordgo.Message
+	scanned := 0
+	before := ""
+
+	for page := 0; page < backfillMaxPages; page++ {
+		batch, err := b.session.ChannelMessages(channelID, backfillPageSize, before, "", "")
+		if err != nil {
+			return nil, scanned, err
+		}
+
+		for _, m := range batch {
+			if m.Timestamp.Before(since) {
+				return reverseMessages(found), scanned, nil
+			}
+			scanned++
+			if m.Author == nil || m.Author.Bot {
+				continue
+			}
+			if len(screenshotAttachments(m)) > 0 {
+				found = append(found, m)
+			}
+		}
+
+		if len(batch) < backfillPageSize {
+			return reverseMessages(found), scanned, nil
+		}
+		before = batch[len(batch)-1].ID
+	}
+
+	b.logger.Warn("Backfill of channel %s stopped after %d messages", channelID, scanned)
+	return reverseMessages(found), scanned, nil
+}
+
+func reverseMessages(messages []*discordgo.Message) []*discordgo.Message {
+	for l, r := 0, len(messages)-1; l < r; l, r = l+1, r-1 {
+		messages[l], messages[r] = messages[r], messages[l]
+	}
+	return messages
+}
@@ -3,6 +3,7 @@ package discord
 import (
 	"context"
 	"strings"
+	"sync"
 	"valhalla/internal/application"
 	"valhalla/pkg/config"
 
@@ -18,6 +19,8 @@ type Bot struct {
 
 	adminIDs         map[string]struct{}
 	allowedChannelID string
+
+	progressMu sync.Mutex
 }
 
 func NewBot(cfg *config.Config, services *application.Service, logger application.Logger) *Bot {
@@ -49,10 +52,13 @@ func (b *Bot) Init() error {
 	b.addCommands(
 		b.newExportCommand(),
 		b.newResetCommand(),
+		b.newSeasonCommand(),
 		b.newSetTimerCommand(),
 		b.newWipeCommand(),
 		b.newDeleteMatchCommand(),
+		b.newRestoreMatchCommand(),
 		b.newSyncSheetCommand(),
+		b.newRebuildStatsCommand(),
 		b.newResetPlayerCommand(),
 		b.newWipePlayerCommand(),
 		b.newRenamePlayerCommand(),
@@ -60,13 +66,26 @@ func (b *Bot) Init() error {
 		b.newTopCommand(),
 		b.newProfileCommand(),
 		b.newHistoryCommand(),
+		b.newVersusCommand(),
+		b.newDuoCommand(),
 		b.newLinkCommand(),
 		b.newUnlinkCommand(),
 		b.newTelegramProfileCommand(),
+		b.newPendingCommand(),
+		b.newAliasCommand(),
+		b.newMatchCommand(),
+		b.newUploadsCommand(),
+		b.newReparseMatchCommand(),
+		b.newGameCommand(),
+		b.newAIUsageCommand(),
+		b.newAddMatchCommand(),
+		b.newEditMatchCommand(),
+		b.newBackfillCommand(),
 	)
 
 	b.session.AddHandler(b.onInteraction)
 	b.session.AddHandler(b.onMessage)
+	b.services.IngestionService.OnUpdate(b.onIngestionUpdate)
 	return nil
 }
 
@@ -99,10 +118,17 @@ func (b *Bot) Stop() {
 }
 
 func (b *Bot) onInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.Type != discordgo.InteractionApplicationCommand {
-		return
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		b.onCommand(s, i)
+	case discordgo.InteractionMessageComponent:
+		b.handleComponent(s, i.Interaction)
+	case discordgo.InteractionModalSubmit:
+		b.handleModalSubmit(s, i.Interaction)
 	}
+}
 
+func (b *Bot) onCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	name := i.ApplicationCommandData().Name
 
 	switch name {
@@ -118,6 +144,12 @@ func (b *Bot) onInteraction(s *discordgo.Session, i *discordgo.InteractionCreate
 	case "history":
 		b.handleHistory(s, i.Interaction)
 		return
+	case "versus":
+		b.handlePair(s, i.Interaction, false)
+		return
+	case "duo":
+		b.handlePair(s, i.Interaction, true)
+		return
 	case "link":
 		b.handleLink(s, i.Interaction)
 		return
@@ -127,9 +159,15 @@ func (b *Bot) onInteraction(s *discordgo.Session, i *discordgo.InteractionCreate
 	case "telegram_profile":
 		b.handleTelegramProfile(s, i.Interaction)
 		return
+	case "match":
+		b.handleMatch(s, i.Interaction)
+		return
+	case "season":
+		b.handleSeason(s, i.Interaction)
+		return
 	}
 
-	if !b.isAdmin(i.Member.User.ID) {
+	if !b.isAdmin(interactionUser(i.Interaction).ID) {
 		b.respondMessage(s, i.Interaction, "У вас нет прав.", true)
 		return
 	}
@@ -145,14 +183,36 @@ func (b *Bot) onInteraction(s *discordgo.Session, i *discordgo.InteractionCreate
 		b.handleResetPlayer(s, i.Interaction)
 	case "sync_sheet":
 		b.handleSyncSheet(s, i.Interaction)
+	case "rebuild_stats":
+		b.handleRebuildStats(s, i.Interaction)
 	case "delete_match":
 		b.handleDeleteMatch(s, i.Interaction)
+	case "restore_match":
+		b.handleRestoreMatch(s, i.Interaction)
 	case "wipe":
 		b.handleWipe(s, i.Interaction)
 	case "wipe_player":
 		b.handleWipePlayer(s, i.Interaction)
 	case "rename_player":
 		b.handleRenamePlayer(s, i.Interaction)
+	case "pending":
+		b.handlePending(s, i.Interaction)
+	case "alias":
+		b.handleAlias(s, i.Interaction)
+	case "uploads":
+		b.handleUploads(s, i.Interaction)
+	case "reparse_match":
+		b.handleReparseMatch(s, i.Interaction)
+	case "game":
+		b.handleGame(s, i.Interaction)
+	case "ai_usage":
+		b.handleAIUsage(s, i.Interaction)
+	case "add_match":
+		b.openAddMatchModal(s, i.Interaction)
+	case "edit_match":
+		b.openEditMatchModal(s, i.Interaction)
+	case "backfill":
+		b.handleBackfill(s, i.Interaction)
 	}
 }
 
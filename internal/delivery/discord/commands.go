@@ -1,6 +1,11 @@
 package discord
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 func (b *Bot) addCommands(commands ...*discordgo.ApplicationCommand) {
 	b.commands = append(b.commands, commands...)
@@ -9,7 +14,34 @@ func (b *Bot) addCommands(commands ...*discordgo.ApplicationCommand) {
 func (b *Bot) newResetCommand() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        "reset",
-		Description: "Сброс сезона (Только админы)",
+		Description: "Закрыть сезон с сохранением итогов и начать новый (Только админы)",
+	}
+}
+
+func (b *Bot) newSeasonCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "season",
+		Description: "Сезоны: начать, закрыть с сохранением итогов, список",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "start",
+				Description: "Начать новый сезон, текущий будет закрыт (Только админы)",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "Название (по умолчанию «Сезон N»)", Required: false},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "end",
+				Description: "Закрыть текущий сезон и заморозить итоги (Только админы)",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Список сезонов",
+			},
+		},
 	}
 }
 
@@ -17,6 +49,7 @@ func (b *Bot) newExportCommand() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        "export",
 		Description: "Экспорт отчета в Excel (Только админы)",
+		Options:     leaderboardOptions(),
 	}
 }
 
@@ -40,6 +73,23 @@ func (b *Bot) newDeleteMatchCommand() *discordgo.ApplicationCommand {
 	}
 }
 
+func (b *Bot) newRestoreMatchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "restore_match",
+		Description: "Вернуть удалённый матч по ID (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID матча", Required: true},
+		},
+	}
+}
+
+func (b *Bot) newRebuildStatsCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "rebuild_stats",
+		Description: "Проверить и пересчитать статистику и рейтинг по всем матчам (Только админы)",
+	}
+}
+
 func (b *Bot) newWipeCommand() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        "wipe",
@@ -96,18 +146,7 @@ func (b *Bot) newTopCommand() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        "top",
 		Description: "Таблица лидеров",
-		Options: []*discordgo.ApplicationCommandOption{
-			{
-				Type:        discordgo.ApplicationCommandOptionString,
-				Name:        "sort",
-				Description: "Критерий сортировки",
-				Required:    false,
-				Choices: []*discordgo.ApplicationCommandOptionChoice{
-					{Name: "По KDA", Value: "kda"},
-					{Name: "По Винрейту", Value: "winrate"},
-				},
-			},
-		},
+		Options:     leaderboardOptions(),
 	}
 }
 
@@ -117,10 +156,35 @@ func (b *Bot) newProfileCommand() *discordgo.ApplicationCommand {
 		Description: "Статистика игрока (по ID)",
 		Options: []*discordgo.ApplicationCommandOption{
 			{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID игрока", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "season", Description: "Название сезона (по умолчанию текущий)", Required: false},
 		},
 	}
 }
 
+func (b *Bot) newVersusCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "versus",
+		Description: "Личные встречи двух игроков в разных командах (по ID)",
+		Options:     pairOptions(),
+	}
+}
+
+func (b *Bot) newDuoCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "duo",
+		Description: "Результаты двух игроков в одной команде (по ID)",
+		Options:     pairOptions(),
+	}
+}
+
+func pairOptions() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{Type: discordgo.ApplicationCommandOptionInteger, Name: "player", Description: "ID игрока", Required: true},
+		{Type: discordgo.ApplicationCommandOptionInteger, Name: "other", Description: "ID второго игрока", Required: true},
+		{Type: discordgo.ApplicationCommandOptionString, Name: "season", Description: "Название сезона (по умолчанию текущий)", Required: false},
+	}
+}
+
 func (b *Bot) newHistoryCommand() *discordgo.ApplicationCommand {
 	return &discordgo.ApplicationCommand{
 		Name:        "history",
@@ -160,3 +224,162 @@ func (b *Bot) newTelegramProfileCommand() *discordgo.ApplicationCommand {
 		},
 	}
 }
+
+func (b *Bot) newPendingCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "pending",
+		Description: "Матчи, ожидающие проверки (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID матча для проверки", Required: false},
+		},
+	}
+}
+
+func (b *Bot) newAliasCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "alias",
+		Description: "Привязка вариантов написания ника к игроку (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Привязать написание ника к игроку",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "alias", Description: "Ник, как его читает ИИ", Required: true},
+					{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID игрока", Required: true},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Удалить привязку",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "alias", Description: "Ник, как его читает ИИ", Required: true},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Список привязок",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID игрока", Required: false},
+				},
+			},
+		},
+	}
+}
+
+func (b *Bot) newMatchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "match",
+		Description: "Подробности матча: состав, источник скриншота",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID матча", Required: true},
+		},
+	}
+}
+
+func (b *Bot) newUploadsCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "uploads",
+		Description: "Статистика загрузок скриншотов (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "Загрузки пользователя", Required: false},
+		},
+	}
+}
+
+func (b *Bot) newReparseMatchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "reparse_match",
+		Description: "Распознать сохранённый скриншот матча заново (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID матча", Required: true},
+		},
+	}
+}
+
+func (b *Bot) newEditMatchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "edit_match",
+		Description: "Исправить результаты матча: K/D/A, результат, герой, игрок (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "id", Description: "ID матча", Required: true},
+		},
+	}
+}
+
+func (b *Bot) newBackfillCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "backfill",
+		Description: "Загрузить скриншоты из истории канала (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Канал со скриншотами",
+				Required:     true,
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+			},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "since", Description: "С какой даты (YYYY-MM-DD)", Required: true},
+		},
+	}
+}
+
+func (b *Bot) newAddMatchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "add_match",
+		Description: "Внести матч вручную, без скриншота (Только админы)",
+	}
+}
+
+func (b *Bot) newAIUsageCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "ai_usage",
+		Description: "Расход запросов и токенов ИИ, лимиты (Только админы)",
+	}
+}
+
+func (b *Bot) newGameCommand() *discordgo.ApplicationCommand {
+	var gameChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, g := range games.All() {
+		gameChoices = append(gameChoices, &discordgo.ApplicationCommandOptionChoice{Name: g.Name, Value: g.ID})
+	}
+	scopeOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "scope",
+		Description: "Для канала (по умолчанию) или всего сервера",
+		Required:    false,
+		Choices: []*discordgo.ApplicationCommandOptionChoice{
+			{Name: "Этот канал", Value: models.GameScopeChannel},
+			{Name: "Весь сервер", Value: models.GameScopeGuild},
+		},
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        "game",
+		Description: "Игра, скриншоты и статистика которой ведутся здесь (Только админы)",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "show",
+				Description: "Показать текущую игру",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Выбрать игру",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "game", Description: "Игра", Required: true, Choices: gameChoices},
+					scopeOption,
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Вернуть игру по умолчанию",
+				Options:     []*discordgo.ApplicationCommandOption{scopeOption},
+			},
+		},
+	}
+}
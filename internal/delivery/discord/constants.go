@@ -2,9 +2,10 @@ package discord
 
 const (
 	// Display limits
-	topPlayersLimit      = 10
-	maxMessageLength     = 2000
-	maxMessageTruncation = 1990
+	topPlayersLimit     = 10
+	maxMessageLength    = 2000
+	listTruncatedSuffix = "...\n(список обрезан)"
+	profileHeroesLimit  = 3
 
 	// Win rate thresholds for color coding
 	winRateExcellent = 75.0
@@ -20,6 +21,32 @@ const (
 	colorBlue         = 0x3498DB // Info/history
 	colorTelegramBlue = 0x0088CC // Telegram-specific
 
+	// Match review components
+	reviewApproveAction  = "review_approve"
+	reviewEditAction     = "review_edit"
+	reviewRejectAction   = "review_reject"
+	reviewModalAction    = "review_modal"
+	reviewTableInputID   = "review_table"
+	reviewResolveAction  = "review_resolve"
+	reparseApplyAction   = "reparse_apply"
+	reparseCancelAction  = "reparse_cancel"
+	editMatchModalAction = "edit_modal"
+	matchEditsLimit      = 5
+	addMatchModalID      = "add_match_modal"
+	addMatchTableInput   = "add_match_table"
+	addMatchDateInput    = "add_match_date"
+	pendingListLimit     = 25
+	maxComponentRows     = 5
+	buttonLabelLimit     = 80
+	embedDescriptionMax  = 4000
+	embedFieldMax        = 1024
+	modalTitleLimit      = 45
+
+	// Backfill: Discord returns at most 100 messages per history request
+	backfillPageSize     = 100
+	backfillMaxPages     = 200
+	uploadProgressDetail = 10 // larger uploads list only failures in the progress message
+
 	// Guild configuration
 	defaultGuildID = "1458104409677627576"
 )
@@ -0,0 +1,82 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"valhalla/internal/application"
+	"valhalla/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (b *Bot) openEditMatchModal(s *discordgo.Session, i *discordgo.Interaction) {
+	matchID := int(i.ApplicationCommandData().Options[0].IntValue())
+	match, err := b.services.MatchService.GetMatch(matchID)
+	if err != nil {
+		b.respondMessage(s, i, fmt.Sprintf("Матч #%d не найден.", matchID), true)
+		return
+	}
+
+	err = s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: componentID(editMatchModalAction, matchID),
+			Title:    fmt.Sprintf("Исправление матча #%d", matchID),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:  reviewTableInputID,
+						Label:     "ник или #ID | WIN/LOSE | K/D/A | герой",
+						Style:     discordgo.TextInputParagraph,
+						Value:     application.FormatResultsTable(match.Players),
+						Required:  true,
+						MaxLength: 4000,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		b.logger.Error("failed to open edit match modal: %v", err)
+	}
+}
+
+func (b *Bot) handleEditMatchSubmit(s *discordgo.Session, i *discordgo.Interaction, matchID int) {
+	table := modalTextValue(i.ModalSubmitData(), reviewTableInputID)
+	match, changes, err := b.services.MatchService.EditMatch(matchID, table, interactionUser(i).ID)
+	if err != nil {
+		b.respondMessage(s, i, formatManualMatchError(err), true)
+		return
+	}
+
+	summary := truncateLabel(strings.Join(changes, "\n"), embedFieldMax)
+	embed := buildReviewEmbed(match)
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "✏️ Изменения", Value: summary})
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("✏️ Матч #%d исправлен.", match.ID),
+			Embeds:  []*discordgo.MessageEmbed{embed},
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// formatMatchEdits renders the latest audit entries of a match, newest first
+func formatMatchEdits(edits []models.MatchEdit) string {
+	var sb strings.Builder
+	for n, e := range edits {
+		if n == matchEditsLimit {
+			sb.WriteString(fmt.Sprintf("…и ещё %d", len(edits)-n))
+			break
+		}
+		kind := "исправил"
+		if e.Kind == models.MatchEditReparse {
+			kind = "перераспознал"
+		}
+		sb.WriteString(fmt.Sprintf("%s <@%s> %s: %s\n", e.CreatedAt.Format("02.01 15:04"), e.EditedBy, kind,
+			strings.Join(e.Changes, "; ")))
+	}
+	return truncateLabel(sb.String(), embedFieldMax)
+}
@@ -2,21 +2,25 @@ package discord
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
-	"sync"
+	"valhalla/internal/application"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 func (b *Bot) handleTop(s *discordgo.Session, i *discordgo.Interaction) {
-	sortBy := "kda"
-	options := i.ApplicationCommandData().Options
-	if len(options) > 0 {
-		sortBy = options[0].StringValue()
+	q, err := parseLeaderboardQuery(i.ApplicationCommandData().Options, application.SortKDA)
+	if err != nil {
+		b.respondMessage(s, i, err.Error(), true)
+		return
 	}
 
-	stats, err := b.services.MatchService.GetLeaderboard(sortBy)
+	game := b.gameOf(i)
+	stats, err := b.services.MatchService.GetLeaderboard(game, q)
 	if err != nil {
 		b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
 		return
@@ -34,24 +38,14 @@ func (b *Bot) handleTop(s *discordgo.Session, i *discordgo.Interaction) {
 
 	var sb strings.Builder
 	for idx, p := range stats[:topCount] {
-		medal := getMedalEmoji(idx)
-		wr := calculateWinRate(p)
-		kda := calculateKDA(p.Kills, p.Deaths, p.Assists)
-
-		sb.WriteString(fmt.Sprintf("%s %s — WR: `%.0f%%` | KDA: `%.2f` (%d игр)\n",
-			medal, p.Name, wr, kda, p.Matches))
-	}
-
-	title := "Таблица лидеров (по KDA)"
-	if sortBy == "winrate" {
-		title = "Таблица лидеров (по Винрейту)"
+		sb.WriteString(leaderboardLine(idx, p, q.SortBy))
 	}
 
 	embed := &discordgo.MessageEmbed{
-		Title:       title,
+		Title:       fmt.Sprintf("Таблица лидеров (%s)", leaderboardSortNames[q.SortBy]),
 		Description: sb.String(),
 		Color:       colorGold,
-		Footer:      &discordgo.MessageEmbedFooter{Text: "Valhalla Ranked Season"},
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Valhalla Ranked Season • " + describeLeaderboardQuery(q) + " • " + game.Name},
 	}
 
 	s.InteractionRespond(i, &discordgo.InteractionResponse{
@@ -61,16 +55,37 @@ func (b *Bot) handleTop(s *discordgo.Session, i *discordgo.Interaction) {
 }
 
 func (b *Bot) handleProfile(s *discordgo.Session, i *discordgo.Interaction) {
-	id := i.ApplicationCommandData().Options[0].IntValue()
+	var id int64
+	var season string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "id":
+			id = opt.IntValue()
+		case "season":
+			season = opt.StringValue()
+		}
+	}
 
-	p, err := b.services.MatchService.GetPlayerStatsByID(int(id))
+	game := b.gameOf(i)
+	p, err := b.services.MatchService.GetPlayerStatsByID(game, season, int(id))
 	if err != nil {
+		if season != "" {
+			b.respondMessage(s, i, fmt.Sprintf("Игрок с ID %d в сезоне %q не найден: %v", id, season, err), true)
+			return
+		}
 		b.respondMessage(s, i, fmt.Sprintf("Игрок с ID %d не найден.", id), true)
 		return
 	}
 
+	rating := b.formatRating(game, p)
+	footer := game.Name
+	if season != "" {
+		// Recent rating changes belong to the current season, an archived one shows its final rating only
+		rating = fmt.Sprintf("%.0f", p.Rating)
+		footer = season + " • " + game.Name
+	}
+
 	wr := calculateWinRate(p)
-	kda := calculateKDA(p.Kills, p.Deaths, p.Assists)
 	color := getColorByWinRate(wr)
 
 	embed := &discordgo.MessageEmbed{
@@ -79,10 +94,36 @@ func (b *Bot) handleProfile(s *discordgo.Session, i *discordgo.Interaction) {
 		Fields: []*discordgo.MessageEmbedField{
 			{Name: "Матчей", Value: fmt.Sprintf("%d", p.Matches), Inline: true},
 			{Name: "Винрейт", Value: fmt.Sprintf("%.1f%%", wr), Inline: true},
-			{Name: "KDA", Value: fmt.Sprintf("%.2f", kda), Inline: true},
+			{Name: "KDA", Value: fmt.Sprintf("%.2f", p.KDA), Inline: true},
+			{Name: "⭐ Рейтинг", Value: rating, Inline: true},
 			{Name: "Статистика", Value: fmt.Sprintf("⚔️ K: %d | 💀 D: %d | 🤝 A: %d", p.Kills, p.Deaths, p.Assists), Inline: false},
 			{Name: "Результаты", Value: fmt.Sprintf("✅ Побед: %d | ❌ Поражений: %d", p.Wins, p.Losses), Inline: false},
 		},
+		Footer: &discordgo.MessageEmbedFooter{Text: footer},
+	}
+
+	if game.HasStat(games.StatGold) {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Ср. золото", Value: fmt.Sprintf("%.0f", averagePerMatch(p.Gold, p.Matches)), Inline: true})
+	}
+	if game.HasStat(games.StatHeroDamage) {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Ср. урон", Value: fmt.Sprintf("%.0f", averagePerMatch(p.HeroDamage, p.Matches)), Inline: true})
+	}
+	if game.HasStat(games.StatMedal) {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "🏅 MVP", Value: fmt.Sprintf("%d", p.MVPs), Inline: true})
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name: "Герои", Value: valueOrDefault(strings.Join(p.TopHeroes(profileHeroesLimit), ", "), "—"), Inline: false,
+	})
+
+	best, worst, err := b.services.MatchService.GetTeammates(game, season, int(id))
+	if err != nil {
+		b.logger.Error("failed to get teammates of player %d: %v", id, err)
+	}
+	if len(best) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "🤝 Лучшие тиммейты", Value: formatTeammates(best), Inline: true})
+	}
+	if len(worst) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "💔 Худшие тиммейты", Value: formatTeammates(worst), Inline: true})
 	}
 
 	s.InteractionRespond(i, &discordgo.InteractionResponse{
@@ -104,11 +145,7 @@ func (b *Bot) handlePlayersList(s *discordgo.Session, i *discordgo.Interaction)
 		sb.WriteString(fmt.Sprintf("`[%d]` **%s**\n", p.ID, p.Name))
 	}
 
-	msg := sb.String()
-	if len(msg) > maxMessageLength {
-		msg = msg[:maxMessageTruncation] + "...\n(список обрезан)"
-	}
-
+	msg := truncateMessage(sb.String(), listTruncatedSuffix)
 	b.respondMessage(s, i, msg, false)
 }
 
@@ -130,7 +167,7 @@ func (b *Bot) handleHistory(s *discordgo.Session, i *discordgo.Interaction) {
 		Title:       fmt.Sprintf("История матчей (ID: %d)", id),
 		Description: strings.Join(lines, "\n"),
 		Color:       colorBlue,
-		Footer:      &discordgo.MessageEmbedFooter{Text: "ID Матча | Результат | K/D/A | Дата"},
+		Footer:      &discordgo.MessageEmbedFooter{Text: "ID Матча | Результат | K/D/A | Дата • /match id:<ID> — подробности"},
 	}
 
 	s.InteractionRespond(i, &discordgo.InteractionResponse{
@@ -193,11 +230,17 @@ func (b *Bot) handleWipe(s *discordgo.Session, i *discordgo.Interaction) {
 }
 
 func (b *Bot) handleExport(s *discordgo.Session, i *discordgo.Interaction) {
+	q, err := parseLeaderboardQuery(i.ApplicationCommandData().Options, application.SortMatches)
+	if err != nil {
+		b.respondMessage(s, i, err.Error(), true)
+		return
+	}
+
 	s.InteractionRespond(i, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	})
 
-	data, err := b.services.MatchService.GetExcelReport()
+	data, err := b.services.MatchService.GetExcelReport(b.gameOf(i), q)
 	if err != nil {
 		b.logger.Error("Export error: %v", err)
 		s.InteractionResponseEdit(i, &discordgo.WebhookEdit{
@@ -215,12 +258,12 @@ func (b *Bot) handleExport(s *discordgo.Session, i *discordgo.Interaction) {
 }
 
 func (b *Bot) handleReset(s *discordgo.Session, i *discordgo.Interaction) {
-	err := b.services.MatchService.ResetGlobal()
+	started, closed, err := b.services.MatchService.StartSeason("", interactionUser(i).ID)
 	if err != nil {
 		b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
-	} else {
-		b.respondMessage(s, i, "Статистика сезона полностью сброшена.", false)
+		return
 	}
+	b.respondMessage(s, i, formatSeasonStarted(started, closed), false)
 }
 
 func (b *Bot) handleSetTimer(s *discordgo.Session, i *discordgo.Interaction) {
@@ -235,6 +278,23 @@ func (b *Bot) handleSetTimer(s *discordgo.Session, i *discordgo.Interaction) {
 	}
 }
 
+func (b *Bot) handleRebuildStats(s *discordgo.Session, i *discordgo.Interaction) {
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	stale, err := b.services.MatchService.RebuildStats()
+	content := "✅ Статистика и рейтинг пересчитаны, расхождений не найдено."
+	switch {
+	case err != nil:
+		content = "❌ Ошибка пересчёта: " + err.Error()
+	case stale > 0:
+		content = fmt.Sprintf("⚠️ Статистика пересчитана, исправлено записей игроков: %d.", stale)
+	}
+	s.InteractionResponseEdit(i, &discordgo.WebhookEdit{Content: &content})
+}
+
 func (b *Bot) handleSyncSheet(s *discordgo.Session, i *discordgo.Interaction) {
 	s.InteractionRespond(i, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
@@ -249,7 +309,7 @@ func (b *Bot) handleSyncSheet(s *discordgo.Session, i *discordgo.Interaction) {
 	}
 
 	s.InteractionResponseEdit(i, &discordgo.WebhookEdit{
-		Content: &[]string{fmt.Sprintf("Таблица успешно обновлена!\nСсылка: %s", url)}[0],
+		Content: &[]string{fmt.Sprintf("Таблица успешно обновлена!\nПервый лист — игра по умолчанию, у остальных игр свои листы.\nСсылка: %s", url)}[0],
 	})
 }
 
@@ -265,6 +325,18 @@ func (b *Bot) handleDeleteMatch(s *discordgo.Session, i *discordgo.Interaction)
 	b.respondMessage(s, i, fmt.Sprintf("Матч #%d успешно удален из базы.", id), false)
 }
 
+func (b *Bot) handleRestoreMatch(s *discordgo.Session, i *discordgo.Interaction) {
+	id := i.ApplicationCommandData().Options[0].IntValue()
+
+	match, err := b.services.MatchService.RestoreMatch(int(id))
+	if err != nil {
+		b.respondMessage(s, i, fmt.Sprintf("Ошибка восстановления: %v", err), true)
+		return
+	}
+
+	b.respondMessage(s, i, fmt.Sprintf("Матч #%d восстановлен (%s).", match.ID, match.Status), false)
+}
+
 func (b *Bot) handleRenamePlayer(s *discordgo.Session, i *discordgo.Interaction) {
 	opts := i.ApplicationCommandData().Options
 	id := int(opts[0].IntValue())
@@ -285,90 +357,277 @@ func (b *Bot) handleRenamePlayer(s *discordgo.Session, i *discordgo.Interaction)
 	b.respondMessage(s, i, fmt.Sprintf("Игрок переименован:\n**%s** → **%s**", oldName, newName), false)
 }
 
-func (b *Bot) handleScreenshots(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// Filter only image attachments
-	var imageAttachments []*discordgo.MessageAttachment
-	for _, att := range m.Attachments {
-		filename := strings.ToLower(att.Filename)
-		if strings.HasSuffix(filename, ".png") ||
-			strings.HasSuffix(filename, ".jpg") ||
-			strings.HasSuffix(filename, ".jpeg") {
-			imageAttachments = append(imageAttachments, att)
+func (b *Bot) handleAlias(s *discordgo.Session, i *discordgo.Interaction) {
+	sub := i.ApplicationCommandData().Options[0]
+
+	switch sub.Name {
+	case "add":
+		alias := sub.Options[0].StringValue()
+		id := int(sub.Options[1].IntValue())
+		if err := b.services.MatchService.AddAlias(alias, id, interactionUser(i).ID); err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		name, _ := b.services.MatchService.GetPlayerNameByID(id)
+		b.respondMessage(s, i, fmt.Sprintf("Ник `%s` теперь распознаётся как **%s** (ID: %d)", alias, name, id), false)
+	case "remove":
+		alias := sub.Options[0].StringValue()
+		if err := b.services.MatchService.RemoveAlias(alias); err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.respondMessage(s, i, fmt.Sprintf("Привязка `%s` удалена.", alias), false)
+	case "list":
+		id := 0
+		if len(sub.Options) > 0 {
+			id = int(sub.Options[0].IntValue())
+		}
+		aliases, err := b.services.MatchService.GetAliases(id)
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		if len(aliases) == 0 {
+			b.respondMessage(s, i, "Привязок пока нет.", true)
+			return
+		}
+
+		var sb strings.Builder
+		for _, a := range aliases {
+			source := "вручную"
+			if a.Source == models.AliasSourceFuzzy {
+				source = "авто"
+			}
+			sb.WriteString(fmt.Sprintf("`%s` → **%s** (ID: %d, %s)\n", a.Alias, a.PlayerName, a.PlayerID, source))
 		}
+
+		msg := truncateMessage(sb.String(), listTruncatedSuffix)
+		b.respondMessage(s, i, msg, true)
 	}
+}
 
-	if len(imageAttachments) == 0 {
+func (b *Bot) handleGame(s *discordgo.Session, i *discordgo.Interaction) {
+	sub := i.ApplicationCommandData().Options[0]
+
+	scope := models.GameScopeChannel
+	var gameID string
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "scope":
+			scope = opt.StringValue()
+		case "game":
+			gameID = opt.StringValue()
+		}
+	}
+	scopeID, scopeName := i.ChannelID, "канала"
+	if scope == models.GameScopeGuild {
+		scopeID, scopeName = i.GuildID, "сервера"
+	}
+
+	switch sub.Name {
+	case "show":
+		game := b.gameOf(i)
+		sel, err := b.services.MatchService.GetGameSelection(i.GuildID, i.ChannelID)
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		origin := "по умолчанию"
+		if sel != nil {
+			origin = "выбрана для сервера"
+			if sel.Scope == models.GameScopeChannel {
+				origin = "выбрана для канала"
+			}
+			origin += fmt.Sprintf(" <@%s> %s", sel.SelectedBy, sel.SelectedAt.Format("02.01.2006"))
+		}
+		b.respondMessage(s, i, fmt.Sprintf("🎮 Игра: **%s** (%s)\nИгроков в команде: %d", game.Name, origin, game.PlayersPerTeam), true)
+	case "set":
+		game, err := b.services.MatchService.SetGame(scope, scopeID, gameID, interactionUser(i).ID)
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.respondMessage(s, i, fmt.Sprintf("🎮 Игра %s: **%s**. Новые скриншоты распознаются и считаются для неё.", scopeName, game.Name), false)
+	case "reset":
+		if err := b.services.MatchService.ResetGame(scope, scopeID); err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.respondMessage(s, i, fmt.Sprintf("🎮 Выбор игры для %s сброшен, теперь: **%s**.", scopeName, b.gameOf(i).Name), false)
+	}
+}
+
+func (b *Bot) handleUploads(s *discordgo.Session, i *discordgo.Interaction) {
+	options := i.ApplicationCommandData().Options
+	if len(options) > 0 {
+		user := options[0].UserValue(s)
+		matches, err := b.services.MatchService.GetMatchesBySubmitter(models.PlatformDiscord, user.ID)
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		if len(matches) == 0 {
+			b.respondMessage(s, i, fmt.Sprintf("<@%s> не загружал скриншотов.", user.ID), true)
+			return
+		}
+
+		var sb strings.Builder
+		for _, m := range matches {
+			sb.WriteString(fmt.Sprintf("🆔 **%d** | %s | %s\n", m.ID, m.CreatedAt.Format("02.01 15:04"), formatMatchStatus(m.Status)))
+		}
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("Загрузки %s", user.Username),
+			Description: sb.String(),
+			Color:       colorBlue,
+			Footer:      &discordgo.MessageEmbedFooter{Text: "Последние загрузки • /match id:<ID> — подробности"},
+		}
+		s.InteractionRespond(i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+				Flags:  discordgo.MessageFlagsEphemeral,
+			},
+		})
 		return
 	}
 
-	// Show typing indicator
-	s.ChannelTyping(m.ChannelID)
+	stats, err := b.services.MatchService.GetSubmitterStats()
+	if err != nil {
+		b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+		return
+	}
+	if len(stats) == 0 {
+		b.respondMessage(s, i, "Загрузок пока нет.", true)
+		return
+	}
 
-	// Send processing message
-	msg, _ := s.ChannelMessageSend(m.ChannelID,
-		fmt.Sprintf("⏳ Анализирую %d скриншот(ов)...", len(imageAttachments)))
+	var sb strings.Builder
+	for _, st := range stats {
+		who := st.SubmitterName
+		if st.Platform == models.PlatformDiscord {
+			who = fmt.Sprintf("<@%s>", st.SubmitterID)
+		}
+		sb.WriteString(fmt.Sprintf("%s (%s): **%d** | ✅ %d | ⏳ %d | ❌ %d | 🗑 %d | %s\n",
+			who, st.Platform, st.Total, st.Approved, st.Pending, st.Rejected, st.Deleted, st.LastUpload.Format("02.01 15:04")))
+	}
 
-	// Process images concurrently
-	type result struct {
-		matchID int
-		err     error
-		index   int
+	msg := truncateMessage(sb.String(), listTruncatedSuffix)
+	b.respondMessage(s, i, msg, true)
+}
+
+func (b *Bot) handleAIUsage(s *discordgo.Session, i *discordgo.Interaction) {
+	report, err := b.services.AIUsageService.GetUsageReport(i.GuildID)
+	if err != nil {
+		b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+		return
 	}
 
-	results := make([]result, len(imageAttachments))
-	semaphore := make(chan struct{}, 3) // max 3 concurrent requests
+	budget := report.Budget
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Всего за сегодня", Value: formatAIUsage(report.Today, budget.DailyCalls, budget.DailyTokens), Inline: true},
+		{Name: "Всего за месяц", Value: formatAIUsage(report.Month, budget.MonthlyCalls, budget.MonthlyTokens), Inline: true},
+		{Name: "\u200b", Value: "\u200b", Inline: false},
+		{Name: "Сервер за сегодня", Value: formatAIUsage(report.GuildToday, budget.GuildDailyCalls, 0), Inline: true},
+		{Name: "Сервер за месяц", Value: formatAIUsage(report.GuildMonth, budget.GuildMonthlyCalls, 0), Inline: true},
+	}
 
-	var wg sync.WaitGroup
-	for i, att := range imageAttachments {
-		wg.Add(1)
-		go func(idx int, attachment *discordgo.MessageAttachment) {
-			defer wg.Done()
+	if len(report.TopUsers) > 0 {
+		var sb strings.Builder
+		for _, u := range report.TopUsers {
+			who := valueOrDefault(u.UserName, u.UserID)
+			if u.Platform == models.PlatformDiscord {
+				who = fmt.Sprintf("<@%s>", u.UserID)
+			}
+			sb.WriteString(fmt.Sprintf("%s: **%d** запр. | %d ток.", who, u.Calls, u.TotalTokens))
+			if u.Blocked > 0 {
+				sb.WriteString(fmt.Sprintf(" | ⛔ %d", u.Blocked))
+			}
+			sb.WriteString("\n")
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Пользователи сервера за месяц", Value: sb.String()})
+	}
 
-			semaphore <- struct{}{}        // acquire
-			defer func() { <-semaphore }() // release
+	footer := "Лимиты не заданы"
+	if budget.UserDailyCalls > 0 || budget.UserMonthlyCalls > 0 {
+		footer = fmt.Sprintf("Лимит на пользователя: %s в день, %s в месяц",
+			formatLimit(budget.UserDailyCalls), formatLimit(budget.UserMonthlyCalls))
+	} else if budget != (application.AIBudget{}) {
+		footer = "Лимит на пользователя не задан"
+	}
 
-			matchID, err := b.services.MatchService.ProcessImageFromURL(attachment.URL)
-			results[idx] = result{matchID: matchID, err: err, index: idx}
-		}(i, att)
+	embed := &discordgo.MessageEmbed{
+		Title:  "🤖 Расход ИИ",
+		Color:  colorBlue,
+		Fields: fields,
+		Footer: &discordgo.MessageEmbedFooter{Text: footer},
 	}
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
 
-	// Wait for all to complete
-	wg.Wait()
+func (b *Bot) handleScreenshots(s *discordgo.Session, m *discordgo.MessageCreate) {
+	imageAttachments := screenshotAttachments(m.Message)
+	if len(imageAttachments) == 0 {
+		return
+	}
 
-	// Delete processing message
-	if msg != nil {
-		s.ChannelMessageDelete(m.ChannelID, msg.ID)
+	// Progress message, edited by onIngestionUpdate as the jobs finish
+	var replyID string
+	msg, err := s.ChannelMessageSend(m.ChannelID,
+		fmt.Sprintf("⏳ Анализирую %d скриншот(ов)...", len(imageAttachments)))
+	if err != nil {
+		b.logger.Error("failed to send upload progress message: %v", err)
+	} else {
+		replyID = msg.ID
 	}
 
-	// Build response
-	var successCount, duplicateCount, errorCount int
-	var messages []string
+	jobs := make([]models.IngestionJob, 0, len(imageAttachments))
+	for idx, att := range imageAttachments {
+		jobs = append(jobs, screenshotJob(m.Message, m.GuildID, att, idx+1, m.ChannelID, replyID))
+	}
 
-	for _, res := range results {
-		if res.err != nil {
-			if strings.Contains(res.err.Error(), "duplicate match detected") {
-				duplicateCount++
-			} else {
-				errorCount++
-				messages = append(messages,
-					fmt.Sprintf("❌ Скриншот %d: %v", res.index+1, res.err))
-			}
+	if err := b.services.IngestionService.Enqueue(jobs); err != nil {
+		b.logger.Error("failed to enqueue screenshots: %v", err)
+		text := "❌ Не удалось поставить скриншоты в очередь, попробуйте загрузить их ещё раз."
+		if replyID != "" {
+			s.ChannelMessageEdit(m.ChannelID, replyID, text)
 		} else {
-			successCount++
-			messages = append(messages,
-				fmt.Sprintf("✅ Скриншот %d: Матч #%d записан", res.index+1, res.matchID))
+			s.ChannelMessageSend(m.ChannelID, text)
 		}
 	}
+}
 
-	// Summary message
-	summary := fmt.Sprintf("**Обработано: %d скриншотов**\n✅ Успешно: %d\n⚠️ Дубликаты: %d\n❌ Ошибки: %d",
-		len(imageAttachments), successCount, duplicateCount, errorCount)
+// screenshotLabel names the source of a match in upload replies, adding the scoreboard number for collages
+func screenshotLabel(index int, match *models.Match) string {
+	if match.SourceHash != "" {
+		return fmt.Sprintf("Скриншот %d, табло %d", index, match.RegionIndex+1)
+	}
+	return fmt.Sprintf("Скриншот %d", index)
+}
 
-	if len(messages) > 0 {
-		summary += "\n\n" + strings.Join(messages, "\n")
+func formatScreenshotError(index int, err error) string {
+	label := fmt.Sprintf("Скриншот %d", index)
+	var regionErr *application.RegionError
+	if errors.As(err, &regionErr) {
+		label = fmt.Sprintf("Скриншот %d, табло %d", index, regionErr.Region)
+		err = regionErr.Err
 	}
 
-	s.ChannelMessageSend(m.ChannelID, summary)
+	var validationErr *application.MatchValidationError
+	if errors.As(err, &validationErr) {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("❌ %s: распознавание не прошло проверку", label))
+		for _, v := range validationErr.Violations {
+			sb.WriteString("\n   • " + v)
+		}
+		return sb.String()
+	}
+	return fmt.Sprintf("❌ %s: %v", label, err)
 }
 
 func (b *Bot) handleLink(s *discordgo.Session, i *discordgo.Interaction) {
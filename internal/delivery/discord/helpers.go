@@ -1,6 +1,16 @@
 package discord
 
-import "valhalla/internal/application"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"valhalla/internal/application"
+	"valhalla/internal/games"
+	"valhalla/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 func calculateWinRate(stats *application.PlayerStats) float64 {
 	if stats.Matches == 0 {
@@ -9,12 +19,43 @@ func calculateWinRate(stats *application.PlayerStats) float64 {
 	return (float64(stats.Wins) / float64(stats.Matches)) * 100
 }
 
-func calculateKDA(kills, deaths, assists int) float64 {
-	d := deaths
-	if d == 0 {
-		d = 1
+// gameOf returns the game played in the channel the interaction came from
+func (b *Bot) gameOf(i *discordgo.Interaction) *games.Profile {
+	return b.services.MatchService.GetGame(i.GuildID, i.ChannelID)
+}
+
+// interactionUser returns who triggered the interaction, Member is only set inside a guild and User only in DMs
+func interactionUser(i *discordgo.Interaction) *discordgo.User {
+	if i.Member != nil {
+		return i.Member.User
 	}
-	return float64(kills+assists) / float64(d)
+	return i.User
+}
+
+// formatRating shows the current rating followed by the changes of the last matches, newest first
+func (b *Bot) formatRating(game *games.Profile, stats *application.PlayerStats) string {
+	text := fmt.Sprintf("%.0f", stats.Rating)
+
+	changes, err := b.services.MatchService.GetRatingHistory(game, stats.ID)
+	if err != nil {
+		b.logger.Error("failed to get rating history of player %d: %v", stats.ID, err)
+		return text
+	}
+	var deltas []string
+	for _, c := range changes {
+		deltas = append(deltas, fmt.Sprintf("%+.0f", c.Delta()))
+	}
+	if len(deltas) > 0 {
+		text += " (" + strings.Join(deltas, ", ") + ")"
+	}
+	return text
+}
+
+func averagePerMatch(total, matches int) float64 {
+	if matches == 0 {
+		return 0.0
+	}
+	return float64(total) / float64(matches)
 }
 
 func getColorByWinRate(winRate float64) int {
@@ -49,3 +90,114 @@ func valueOrDefault(value, defaultValue string) string {
 	}
 	return value
 }
+
+// componentID builds a custom ID for buttons and modals in the form "action:id[:id...]"
+func componentID(action string, ids ...int) string {
+	var sb strings.Builder
+	sb.WriteString(action)
+	for _, id := range ids {
+		sb.WriteString(fmt.Sprintf(":%d", id))
+	}
+	return sb.String()
+}
+
+func parseComponentID(customID string) (string, []int, bool) {
+	parts := strings.Split(customID, ":")
+	if len(parts) < 2 {
+		return "", nil, false
+	}
+	ids := make([]int, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return "", nil, false
+		}
+		ids = append(ids, id)
+	}
+	return parts[0], ids, true
+}
+
+// formatAIUsage renders usage totals next to their limits, a zero limit is shown as unlimited
+func formatAIUsage(t models.AIUsageTotals, callLimit, tokenLimit int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Запросы: **%d** / %s\n", t.Calls, formatLimit(callLimit)))
+	sb.WriteString(fmt.Sprintf("Токены: **%d**", t.TotalTokens))
+	if tokenLimit > 0 {
+		sb.WriteString(fmt.Sprintf(" / %d", tokenLimit))
+	}
+	sb.WriteString(fmt.Sprintf("\nОшибки: %d\nОтклонено лимитом: %d", t.Failures, t.Blocked))
+	if t.Calls > 0 {
+		sb.WriteString(fmt.Sprintf("\nСреднее время: %.1f с", t.AvgLatency.Seconds()))
+	}
+	return sb.String()
+}
+
+func formatLimit(limit int) string {
+	if limit <= 0 {
+		return "∞"
+	}
+	return strconv.Itoa(limit)
+}
+
+// screenshotAttachments returns the attachments of a message the scoreboard pipeline can read
+func screenshotAttachments(m *discordgo.Message) []*discordgo.MessageAttachment {
+	var images []*discordgo.MessageAttachment
+	for _, att := range m.Attachments {
+		filename := strings.ToLower(att.Filename)
+		if strings.HasSuffix(filename, ".png") ||
+			strings.HasSuffix(filename, ".jpg") ||
+			strings.HasSuffix(filename, ".jpeg") ||
+			strings.HasSuffix(filename, ".webp") {
+			images = append(images, att)
+		}
+	}
+	return images
+}
+
+// screenshotJob builds the ingestion job of one attachment. guildID is passed separately since messages
+// loaded from channel history do not carry it. Progress is reported by editing the reply message, the post
+// time dates matches whose scoreboard shows no end time
+func screenshotJob(m *discordgo.Message, guildID string, att *discordgo.MessageAttachment, index int, replyChannelID, replyID string) models.IngestionJob {
+	return models.IngestionJob{
+		Source: models.MatchSource{
+			Platform:      models.PlatformDiscord,
+			SubmitterID:   m.Author.ID,
+			SubmitterName: m.Author.Username,
+			GuildID:       guildID,
+			ChannelID:     m.ChannelID,
+			MessageID:     m.ID,
+			AttachmentURL: att.URL,
+			PostedAt:      m.Timestamp,
+		},
+		AttachmentIndex: index,
+		ReplyChannelID:  replyChannelID,
+		ReplyMessageID:  replyID,
+	}
+}
+
+func countUnresolved(players []models.PlayerResult) int {
+	count := 0
+	for i := range players {
+		if players[i].Unresolved() {
+			count++
+		}
+	}
+	return count
+}
+
+// truncateMessage fits a message into the Discord length limit, cutting whole characters and ending with the suffix
+func truncateMessage(msg, suffix string) string {
+	if utf8.RuneCountInString(msg) <= maxMessageLength {
+		return msg
+	}
+	keep := maxMessageLength - utf8.RuneCountInString(suffix)
+	return string([]rune(msg)[:keep]) + suffix
+}
+
+func truncateLabel(label string, limit int) string {
+	runes := []rune(label)
+	if len(runes) <= limit {
+		return label
+	}
+	return string(runes[:limit-1]) + "…"
+}
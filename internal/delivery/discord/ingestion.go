@@ -0,0 +1,120 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"valhalla/internal/models"
+)
+
+// onIngestionUpdate sends review cards for freshly parsed matches and refreshes the progress message of the upload
+func (b *Bot) onIngestionUpdate(job models.IngestionJob) {
+	if job.Source.Platform != models.PlatformDiscord {
+		return
+	}
+
+	if job.Status == models.JobStatusDone {
+		for _, id := range job.MatchIDs {
+			match, err := b.services.MatchService.GetMatch(id)
+			if err != nil {
+				continue
+			}
+			if match.Status == models.MatchStatusPending {
+				b.sendReviewCard(b.session, job.ReplyChannelID, match)
+			}
+		}
+	}
+
+	if job.ReplyMessageID == "" {
+		return
+	}
+
+	// Workers finish jobs of the same upload concurrently, the last edit must see all of them
+	b.progressMu.Lock()
+	defer b.progressMu.Unlock()
+
+	jobs, err := b.services.IngestionService.GetJobsByReply(job.ReplyChannelID, job.ReplyMessageID)
+	if err != nil {
+		b.logger.Error("failed to load upload jobs: %v", err)
+		return
+	}
+
+	content := truncateMessage(b.formatUploadProgress(jobs), "...")
+	if _, err := b.session.ChannelMessageEdit(job.ReplyChannelID, job.ReplyMessageID, content); err != nil {
+		b.logger.Error("failed to edit upload progress message: %v", err)
+	}
+}
+
+// formatUploadProgress summarizes the jobs of an upload. Large uploads such as a backfill list only
+// retries and failures, successful matches have their own review cards or are already counted
+func (b *Bot) formatUploadProgress(jobs []models.IngestionJob) string {
+	var successCount, duplicateCount, errorCount, finished int
+	var messages []string
+	detailed := len(jobs) <= uploadProgressDetail
+
+	for _, job := range jobs {
+		if !job.Finished() {
+			if job.LastError == "" && !detailed {
+				continue
+			}
+			line := fmt.Sprintf("⏳ Скриншот %d: в очереди", job.AttachmentIndex)
+			if job.LastError != "" {
+				line = fmt.Sprintf("🔁 Скриншот %d: попытка %d/%d не удалась, повтор в %s (%s)",
+					job.AttachmentIndex, job.Attempts, job.MaxAttempts, job.NextAttemptAt.Local().Format("15:04"), job.LastError)
+			}
+			messages = append(messages, line)
+			continue
+		}
+
+		finished++
+		duplicateCount += job.Duplicates
+		for _, id := range job.MatchIDs {
+			successCount++
+			if detailed {
+				messages = append(messages, b.formatUploadedMatch(job.AttachmentIndex, id))
+			}
+		}
+		for _, e := range job.Errors {
+			errorCount++
+			messages = append(messages, fmt.Sprintf("❌ Скриншот %d: %s", job.AttachmentIndex, e))
+		}
+	}
+
+	summary := fmt.Sprintf("**Обработано: %d из %d скриншотов**\n✅ Успешно: %d\n⚠️ Дубликаты: %d\n❌ Ошибки: %d",
+		finished, len(jobs), successCount, duplicateCount, errorCount)
+	if finished == len(jobs) {
+		summary = fmt.Sprintf("**Обработано: %d скриншотов**\n✅ Успешно: %d\n⚠️ Дубликаты: %d\n❌ Ошибки: %d",
+			len(jobs), successCount, duplicateCount, errorCount)
+	}
+
+	if len(messages) > 0 {
+		summary += "\n\n" + strings.Join(messages, "\n")
+	}
+	return summary
+}
+
+func (b *Bot) formatUploadedMatch(index, matchID int) string {
+	match, err := b.services.MatchService.GetMatch(matchID)
+	if err != nil {
+		return fmt.Sprintf("🗑 Скриншот %d: Матч #%d удалён", index, matchID)
+	}
+
+	label := screenshotLabel(index, match)
+	if match.Status == models.MatchStatusApproved {
+		if match.ReviewedBy == models.ReviewerAuto {
+			return fmt.Sprintf("✅ %s: Матч #%d засчитан автоматически", label, match.ID)
+		}
+		return fmt.Sprintf("✅ %s: Матч #%d подтверждён", label, match.ID)
+	}
+	if match.Status == models.MatchStatusRejected {
+		return fmt.Sprintf("❌ %s: Матч #%d отклонён", label, match.ID)
+	}
+
+	line := fmt.Sprintf("⏳ %s: Матч #%d ожидает проверки", label, match.ID)
+	if match.PossibleDuplicateOf != 0 {
+		line += fmt.Sprintf(" (⚠️ возможный дубликат матча #%d)", match.PossibleDuplicateOf)
+	}
+	if unresolved := countUnresolved(match.Players); unresolved > 0 {
+		line += fmt.Sprintf(" (❓ неоднозначных ников: %d)", unresolved)
+	}
+	return line
+}
@@ -0,0 +1,110 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"valhalla/internal/application"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var leaderboardSortNames = map[string]string{
+	application.SortRating:  "по рейтингу",
+	application.SortWinRate: "по винрейту",
+	application.SortKDA:     "по KDA",
+	application.SortKills:   "по убийствам за игру",
+	application.SortDeaths:  "по смертям за игру",
+	application.SortAssists: "по помощи за игру",
+	application.SortMatches: "по числу матчей",
+}
+
+// leaderboardOptions are the sort and filter options shared by /top and /export
+func leaderboardOptions() []*discordgo.ApplicationCommandOption {
+	var sortChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, sortBy := range application.LeaderboardSorts {
+		name := []rune(leaderboardSortNames[sortBy])
+		sortChoices = append(sortChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name: strings.ToUpper(string(name[:1])) + string(name[1:]), Value: sortBy,
+		})
+	}
+	minGames := 0.0
+
+	return []*discordgo.ApplicationCommandOption{
+		{Type: discordgo.ApplicationCommandOptionString, Name: "sort", Description: "Критерий сортировки", Required: false, Choices: sortChoices},
+		{Type: discordgo.ApplicationCommandOptionString, Name: "season", Description: "Название сезона (по умолчанию текущий)", Required: false},
+		{Type: discordgo.ApplicationCommandOptionInteger, Name: "min_games", Description: "Минимум матчей, чтобы попасть в таблицу", Required: false, MinValue: &minGames},
+		{Type: discordgo.ApplicationCommandOptionString, Name: "hero", Description: "Только матчи на этом герое", Required: false},
+		{Type: discordgo.ApplicationCommandOptionString, Name: "role", Description: "Только игроки с этой основной ролью в профиле", Required: false},
+		{Type: discordgo.ApplicationCommandOptionString, Name: "from", Description: "С даты, YYYY-MM-DD", Required: false},
+		{Type: discordgo.ApplicationCommandOptionString, Name: "to", Description: "По дату включительно, YYYY-MM-DD", Required: false},
+	}
+}
+
+func parseLeaderboardQuery(options []*discordgo.ApplicationCommandInteractionDataOption, defaultSort string) (application.LeaderboardQuery, error) {
+	q := application.LeaderboardQuery{SortBy: defaultSort, MinGames: application.UseDefaultMinGames}
+	for _, opt := range options {
+		switch opt.Name {
+		case "sort":
+			q.SortBy = opt.StringValue()
+		case "season":
+			q.Season = opt.StringValue()
+		case "min_games":
+			q.MinGames = int(opt.IntValue())
+		case "hero":
+			q.Hero = opt.StringValue()
+		case "role":
+			q.Role = opt.StringValue()
+		case "from", "to":
+			day, err := time.ParseInLocation("2006-01-02", opt.StringValue(), time.Local)
+			if err != nil {
+				return q, fmt.Errorf("неверный формат даты %q, используйте YYYY-MM-DD", opt.StringValue())
+			}
+			if opt.Name == "from" {
+				q.From = day
+			} else {
+				q.To = day.AddDate(0, 0, 1)
+			}
+		}
+	}
+	return q, nil
+}
+
+// describeLeaderboardQuery lists the season and the filters of a leaderboard for its footer
+func describeLeaderboardQuery(q application.LeaderboardQuery) string {
+	parts := []string{valueOrDefault(q.Season, "текущий сезон")}
+	if q.MinGames > 0 {
+		parts = append(parts, fmt.Sprintf("от %d игр", q.MinGames))
+	}
+	if q.Hero != "" {
+		parts = append(parts, "герой: "+q.Hero)
+	}
+	if q.Role != "" {
+		parts = append(parts, "роль: "+q.Role)
+	}
+	if !q.From.IsZero() || !q.To.IsZero() {
+		period := "…"
+		if !q.From.IsZero() {
+			period = q.From.Format("02.01.2006") + " " + period
+		}
+		if !q.To.IsZero() {
+			period += " " + q.To.AddDate(0, 0, -1).Format("02.01.2006")
+		}
+		parts = append(parts, period)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// leaderboardLine shows a player's rating, win rate and KDA, plus the per-match average a K/D/A sort ranks by
+func leaderboardLine(idx int, p *application.PlayerStats, sortBy string) string {
+	line := fmt.Sprintf("%s %s — ⭐ `%.0f` | WR: `%.0f%%` | KDA: `%.2f`", getMedalEmoji(idx), p.Name, p.Rating, calculateWinRate(p), p.KDA)
+	switch sortBy {
+	case application.SortKills:
+		line += fmt.Sprintf(" | ⚔️ `%.1f`/игра", averagePerMatch(p.Kills, p.Matches))
+	case application.SortDeaths:
+		line += fmt.Sprintf(" | 💀 `%.1f`/игра", averagePerMatch(p.Deaths, p.Matches))
+	case application.SortAssists:
+		line += fmt.Sprintf(" | 🤝 `%.1f`/игра", averagePerMatch(p.Assists, p.Matches))
+	}
+	return line + fmt.Sprintf(" (%d игр)\n", p.Matches)
+}
@@ -0,0 +1,102 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"valhalla/internal/application"
+	"valhalla/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (b *Bot) openAddMatchModal(s *discordgo.Session, i *discordgo.Interaction) {
+	game := b.gameOf(i)
+
+	// One line per player, winners first, so the admin only overwrites names and numbers
+	lines := make([]string, 0, game.PlayersPerMatch())
+	for _, result := range []string{"WIN", "LOSE"} {
+		for n := 0; n < game.PlayersPerTeam; n++ {
+			lines = append(lines, fmt.Sprintf("ник | %s | 0/0/0", result))
+		}
+	}
+
+	err := s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: addMatchModalID,
+			Title:    truncateLabel("Новый матч: "+game.Name, modalTitleLimit),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:  addMatchTableInput,
+						Label:     "ник | WIN/LOSE | K/D/A | герой",
+						Style:     discordgo.TextInputParagraph,
+						Value:     strings.Join(lines, "\n"),
+						Required:  true,
+						MaxLength: 4000,
+					},
+				}},
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:    addMatchDateInput,
+						Label:       "Дата матча (по умолчанию — сейчас)",
+						Style:       discordgo.TextInputShort,
+						Placeholder: "ДД.ММ.ГГГГ ЧЧ:ММ",
+						Required:    false,
+						MaxLength:   16,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		b.logger.Error("failed to open add match modal: %v", err)
+	}
+}
+
+func (b *Bot) handleAddMatchSubmit(s *discordgo.Session, i *discordgo.Interaction) {
+	user := interactionUser(i)
+	if !b.isAdmin(user.ID) {
+		b.respondMessage(s, i, "У вас нет прав.", true)
+		return
+	}
+
+	data := i.ModalSubmitData()
+	source := models.MatchSource{
+		Platform:      models.PlatformDiscord,
+		SubmitterID:   user.ID,
+		SubmitterName: user.Username,
+		GuildID:       i.GuildID,
+		ChannelID:     i.ChannelID,
+	}
+	match, err := b.services.MatchService.AddManualMatch(b.gameOf(i),
+		modalTextValue(data, addMatchTableInput), modalTextValue(data, addMatchDateInput), source)
+	if err != nil {
+		b.respondMessage(s, i, formatManualMatchError(err), true)
+		return
+	}
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("✍️ Матч #%d внесён вручную и засчитан.", match.ID),
+			Embeds:  []*discordgo.MessageEmbed{buildReviewEmbed(match)},
+		},
+	})
+}
+
+func formatManualMatchError(err error) string {
+	var validationErr *application.MatchValidationError
+	var duplicateErr *application.DuplicateMatchError
+	switch {
+	case errors.As(err, &validationErr):
+		return "❌ Матч не сохранён, таблица не прошла проверку:\n• " + strings.Join(validationErr.Violations, "\n• ")
+	case errors.As(err, &duplicateErr):
+		return fmt.Sprintf("⚠️ Такой матч уже есть: #%d.", duplicateErr.MatchID)
+	case errors.Is(err, application.ErrDuplicateMatch):
+		return "⚠️ Такой матч уже есть."
+	default:
+		return "❌ Матч не сохранён: " + err.Error()
+	}
+}
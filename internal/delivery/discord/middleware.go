@@ -24,7 +24,7 @@ func (b *Bot) respondMessage(s *discordgo.Session, i *discordgo.Interaction, msg
 }
 
 func (b *Bot) ensureAdmin(s *discordgo.Session, i *discordgo.Interaction, handler func(*discordgo.Session, *discordgo.Interaction)) {
-	if !b.isAdmin(i.Member.User.ID) {
+	if !b.isAdmin(interactionUser(i).ID) {
 		b.respondMessage(s, i, "У вас нет прав.", true)
 		return
 	}
@@ -0,0 +1,523 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"valhalla/internal/application"
+	"valhalla/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (b *Bot) handlePending(s *discordgo.Session, i *discordgo.Interaction) {
+	options := i.ApplicationCommandData().Options
+	if len(options) > 0 {
+		id := int(options[0].IntValue())
+		match, err := b.services.MatchService.GetMatch(id)
+		if err != nil {
+			b.respondMessage(s, i, fmt.Sprintf("Матч #%d не найден.", id), true)
+			return
+		}
+
+		s.InteractionRespond(i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{buildReviewEmbed(match)},
+				Components: reviewComponents(match),
+			},
+		})
+		return
+	}
+
+	matches, err := b.services.MatchService.GetPendingMatches()
+	if err != nil {
+		b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+		return
+	}
+
+	if len(matches) == 0 {
+		b.respondMessage(s, i, "Нет матчей, ожидающих проверки.", true)
+		return
+	}
+
+	var sb strings.Builder
+	for idx, m := range matches {
+		if idx == pendingListLimit {
+			sb.WriteString(fmt.Sprintf("...и ещё %d\n", len(matches)-pendingListLimit))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("🆔 **%d** | %s | %d игроков", m.ID, m.CreatedAt.Format("02.01 15:04"), len(m.Players)))
+		if unresolved := countUnresolved(m.Players); unresolved > 0 {
+			sb.WriteString(fmt.Sprintf(" | ❓ %d", unresolved))
+		}
+		sb.WriteString("\n")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Ожидают проверки: %d", len(matches)),
+		Description: sb.String(),
+		Color:       colorGray,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Используйте /pending id:<ID> для проверки"},
+	}
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func (b *Bot) handleMatch(s *discordgo.Session, i *discordgo.Interaction) {
+	id := int(i.ApplicationCommandData().Options[0].IntValue())
+	match, err := b.services.MatchService.GetMatch(id)
+	if err != nil {
+		b.respondMessage(s, i, fmt.Sprintf("Матч #%d не найден.", id), true)
+		return
+	}
+
+	embed := buildReviewEmbed(match)
+	if edits, err := b.services.MatchService.GetMatchEdits(id); err != nil {
+		b.logger.Warn("failed to get edits of match %d: %v", id, err)
+	} else if len(edits) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "✏️ Правки", Value: formatMatchEdits(edits)})
+	}
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
+
+func (b *Bot) handleReparseMatch(s *discordgo.Session, i *discordgo.Interaction) {
+	id := int(i.ApplicationCommandData().Options[0].IntValue())
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	user := interactionUser(i)
+	requester := models.MatchSource{
+		Platform:      models.PlatformDiscord,
+		SubmitterID:   user.ID,
+		SubmitterName: user.Username,
+		GuildID:       i.GuildID,
+		ChannelID:     i.ChannelID,
+	}
+	res, err := b.services.MatchService.ReparseMatch(id, requester)
+	if err != nil {
+		s.InteractionResponseEdit(i, &discordgo.WebhookEdit{
+			Content: &[]string{formatScreenshotError(1, err)}[0],
+		})
+		return
+	}
+
+	description := "Изменений нет."
+	if len(res.Changes) > 0 {
+		description = strings.Join(res.Changes, "\n")
+	}
+	description = truncateLabel(description, embedDescriptionMax)
+
+	table := formatMatchTable(res.Parsed.Players)
+	if utf8.RuneCountInString(table) > embedFieldMax {
+		table = truncateLabel(table, embedFieldMax-3) + "```"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Перераспознавание матча #%d", id),
+		Description: description,
+		Color:       colorBlue,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Новый результат", Value: table},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "Сохранённые результаты будут заменены только после подтверждения. Новый результат хранится до перезапуска бота"},
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Заменить", Style: discordgo.SuccessButton, CustomID: componentID(reparseApplyAction, id)},
+			discordgo.Button{Label: "Отмена", Style: discordgo.SecondaryButton, CustomID: componentID(reparseCancelAction, id)},
+		}},
+	}
+
+	s.InteractionResponseEdit(i, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+}
+
+func (b *Bot) handleComponent(s *discordgo.Session, i *discordgo.Interaction) {
+	action, ids, ok := parseComponentID(i.MessageComponentData().CustomID)
+	if !ok {
+		return
+	}
+	matchID := ids[0]
+
+	userID := interactionUser(i).ID
+	if !b.isAdmin(userID) {
+		b.respondMessage(s, i, "У вас нет прав.", true)
+		return
+	}
+
+	switch action {
+	case reviewApproveAction:
+		if err := b.services.MatchService.ApproveMatch(matchID, userID); err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.updateReviewCard(s, i, matchID)
+	case reviewRejectAction:
+		if err := b.services.MatchService.RejectMatch(matchID, userID); err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.updateReviewCard(s, i, matchID)
+	case reviewEditAction:
+		b.openReviewEditModal(s, i, matchID)
+	case reviewResolveAction:
+		if len(ids) != 3 {
+			return
+		}
+		if _, err := b.services.MatchService.ResolvePlayer(matchID, ids[1], ids[2], userID); err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.updateReviewCard(s, i, matchID)
+	case reparseApplyAction:
+		match, err := b.services.MatchService.ApplyReparse(matchID, userID)
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		s.InteractionRespond(i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{buildReviewEmbed(match)},
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+	case reparseCancelAction:
+		b.services.MatchService.DiscardReparse(matchID)
+		s.InteractionRespond(i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    fmt.Sprintf("Перераспознавание матча #%d отменено.", matchID),
+				Embeds:     []*discordgo.MessageEmbed{},
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+	}
+}
+
+func (b *Bot) handleModalSubmit(s *discordgo.Session, i *discordgo.Interaction) {
+	data := i.ModalSubmitData()
+	if data.CustomID == addMatchModalID {
+		b.handleAddMatchSubmit(s, i)
+		return
+	}
+
+	action, ids, ok := parseComponentID(data.CustomID)
+	if !ok || (action != reviewModalAction && action != editMatchModalAction) {
+		return
+	}
+	matchID := ids[0]
+
+	if !b.isAdmin(interactionUser(i).ID) {
+		b.respondMessage(s, i, "У вас нет прав.", true)
+		return
+	}
+
+	if action == editMatchModalAction {
+		b.handleEditMatchSubmit(s, i, matchID)
+		return
+	}
+
+	table := modalTextValue(data, reviewTableInputID)
+	if _, err := b.services.MatchService.UpdatePendingMatch(matchID, table); err != nil {
+		b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+		return
+	}
+
+	b.updateReviewCard(s, i, matchID)
+}
+
+func (b *Bot) openReviewEditModal(s *discordgo.Session, i *discordgo.Interaction, matchID int) {
+	match, err := b.services.MatchService.GetMatch(matchID)
+	if err != nil {
+		b.respondMessage(s, i, fmt.Sprintf("Матч #%d не найден.", matchID), true)
+		return
+	}
+	if match.Status != models.MatchStatusPending {
+		b.respondMessage(s, i, fmt.Sprintf("Матч #%d уже обработан.", matchID), true)
+		return
+	}
+
+	err = s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: componentID(reviewModalAction, matchID),
+			Title:    fmt.Sprintf("Редактирование матча #%d", matchID),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:  reviewTableInputID,
+						Label:     "ник | WIN/LOSE | K/D/A | герой",
+						Style:     discordgo.TextInputParagraph,
+						Value:     application.FormatResultsTable(match.Players),
+						Required:  true,
+						MaxLength: 4000,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		b.logger.Error("failed to open review modal: %v", err)
+	}
+}
+
+// updateReviewCard re-renders the review message the interaction was triggered from
+func (b *Bot) updateReviewCard(s *discordgo.Session, i *discordgo.Interaction, matchID int) {
+	match, err := b.services.MatchService.GetMatch(matchID)
+	if err != nil {
+		b.respondMessage(s, i, fmt.Sprintf("Матч #%d не найден.", matchID), true)
+		return
+	}
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{buildReviewEmbed(match)},
+			Components: reviewComponents(match),
+		},
+	})
+}
+
+func (b *Bot) sendReviewCard(s *discordgo.Session, channelID string, match *models.Match) {
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{buildReviewEmbed(match)},
+		Components: reviewComponents(match),
+	})
+	if err != nil {
+		b.logger.Error("failed to send review card for match %d: %v", match.ID, err)
+	}
+}
+
+func buildReviewEmbed(match *models.Match) *discordgo.MessageEmbed {
+	title := fmt.Sprintf("Матч #%d — ожидает проверки", match.ID)
+	color := colorGray
+	switch match.Status {
+	case models.MatchStatusApproved:
+		title = fmt.Sprintf("Матч #%d — подтверждён", match.ID)
+		color = colorGreen
+	case models.MatchStatusRejected:
+		title = fmt.Sprintf("Матч #%d — отклонён", match.ID)
+		color = colorRed
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: formatMatchTable(match.Players),
+		Color:       color,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Ник | Герой | K/D/A"},
+	}
+	if info := formatMatchInfo(match); info != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Матч", Value: info})
+	}
+	source := formatMatchSource(match.Source)
+	if match.Manual {
+		source = strings.TrimSuffix("✍️ внесён вручную | "+source, " | ")
+	}
+	if source != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Источник", Value: source})
+	}
+	if match.PossibleDuplicateOf != 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "⚠️ Внимание",
+			Value: fmt.Sprintf("Возможный дубликат матча #%d (похожий скриншот)", match.PossibleDuplicateOf),
+		})
+	}
+	if conflicts := formatNameConflicts(match.Players); conflicts != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "❓ Неоднозначные ники",
+			Value: conflicts + "\nВыберите игрока кнопками ниже, подтвердить матч можно после выбора.",
+		})
+	}
+	if match.Status == models.MatchStatusPending && match.ReviewNote != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Причина проверки", Value: truncateLabel(match.ReviewNote, embedFieldMax),
+		})
+	}
+	switch match.ReviewedBy {
+	case "":
+	case models.ReviewerAuto:
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Проверил", Value: "автоматически (высокая уверенность)", Inline: true,
+		})
+	default:
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Проверил", Value: fmt.Sprintf("<@%s>", match.ReviewedBy), Inline: true,
+		})
+	}
+	return embed
+}
+
+func reviewComponents(match *models.Match) []discordgo.MessageComponent {
+	if match.Status != models.MatchStatusPending {
+		return []discordgo.MessageComponent{}
+	}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Подтвердить", Style: discordgo.SuccessButton, CustomID: componentID(reviewApproveAction, match.ID)},
+			discordgo.Button{Label: "Исправить", Style: discordgo.PrimaryButton, CustomID: componentID(reviewEditAction, match.ID)},
+			discordgo.Button{Label: "Отклонить", Style: discordgo.DangerButton, CustomID: componentID(reviewRejectAction, match.ID)},
+		}},
+	}
+
+	// One row per ambiguous name, the remaining ones appear as earlier ones get resolved
+	for _, p := range match.Players {
+		if !p.Unresolved() {
+			continue
+		}
+		if len(components) == maxComponentRows {
+			break
+		}
+		var buttons []discordgo.MessageComponent
+		for _, c := range p.Candidates {
+			buttons = append(buttons, discordgo.Button{
+				Label:    truncateLabel(fmt.Sprintf("%s → %s (#%d)", p.PlayerName, c.Name, c.PlayerID), buttonLabelLimit),
+				Style:    discordgo.SecondaryButton,
+				CustomID: componentID(reviewResolveAction, match.ID, p.ID, c.PlayerID),
+			})
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    truncateLabel(fmt.Sprintf("Новый игрок: %s", p.PlayerName), buttonLabelLimit),
+			Style:    discordgo.PrimaryButton,
+			CustomID: componentID(reviewResolveAction, match.ID, p.ID, 0),
+		})
+		components = append(components, discordgo.ActionsRow{Components: buttons})
+	}
+	return components
+}
+
+// formatNameConflicts lists results waiting for a reviewer to pick the player
+func formatNameConflicts(players []models.PlayerResult) string {
+	var lines []string
+	for _, p := range players {
+		if !p.Unresolved() {
+			continue
+		}
+		names := make([]string, 0, len(p.Candidates))
+		for _, c := range p.Candidates {
+			names = append(names, fmt.Sprintf("%s (#%d)", c.Name, c.PlayerID))
+		}
+		lines = append(lines, fmt.Sprintf("**%s** — похож на: %s", p.PlayerName, strings.Join(names, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatMatchInfo renders the match level metadata read from the scoreboard
+func formatMatchInfo(match *models.Match) string {
+	var parts []string
+	if match.BlueScore != 0 || match.RedScore != 0 {
+		parts = append(parts, fmt.Sprintf("🔵 %d : %d 🔴", match.BlueScore, match.RedScore))
+	}
+	if match.DurationSec > 0 {
+		parts = append(parts, fmt.Sprintf("⏱ %d:%02d", match.DurationSec/60, match.DurationSec%60))
+	}
+	if match.GameMode != "" {
+		parts = append(parts, match.GameMode)
+	}
+	if match.PlayedAt != nil {
+		parts = append(parts, "🗓 "+match.PlayedAt.Format("02.01.2006 15:04"))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatMatchSource shows who uploaded the screenshot and links back to the original message
+func formatMatchSource(src models.MatchSource) string {
+	var parts []string
+	switch {
+	case src.Platform == models.PlatformDiscord && src.SubmitterID != "":
+		parts = append(parts, fmt.Sprintf("<@%s>", src.SubmitterID))
+	case src.SubmitterName != "":
+		parts = append(parts, src.SubmitterName)
+	case src.SubmitterID != "":
+		parts = append(parts, src.SubmitterID)
+	}
+	if src.Platform != "" {
+		parts = append(parts, src.Platform)
+	}
+	if src.Platform == models.PlatformDiscord && src.GuildID != "" && src.ChannelID != "" && src.MessageID != "" {
+		parts = append(parts, fmt.Sprintf("[сообщение](https://discord.com/channels/%s/%s/%s)", src.GuildID, src.ChannelID, src.MessageID))
+	}
+	if src.AttachmentURL != "" {
+		parts = append(parts, fmt.Sprintf("[скриншот](%s)", src.AttachmentURL))
+	}
+	return strings.Join(parts, " | ")
+}
+
+func formatMatchStatus(status string) string {
+	switch status {
+	case models.MatchStatusApproved:
+		return "✅ подтверждён"
+	case models.MatchStatusRejected:
+		return "❌ отклонён"
+	default:
+		return "⏳ ожидает проверки"
+	}
+}
+
+// formatMatchTable renders both teams as a monospace table
+func formatMatchTable(players []models.PlayerResult) string {
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	for _, result := range []string{"WIN", "LOSE"} {
+		header := result
+		for _, p := range players {
+			if strings.EqualFold(p.Result, result) && p.Team != "" {
+				header += " (" + p.Team + ")"
+				break
+			}
+		}
+		sb.WriteString(header + "\n")
+		for _, p := range players {
+			if !strings.EqualFold(p.Result, result) {
+				continue
+			}
+			kda := fmt.Sprintf("%d/%d/%d", p.Kills, p.Deaths, p.Assists)
+			marker := " "
+			if p.Unresolved() {
+				marker = "?"
+			}
+			sb.WriteString(fmt.Sprintf("%s%-16.16s %-12.12s %s", marker, p.PlayerName, valueOrDefault(p.Champion, "—"), kda))
+			if p.Medal == models.MedalMVP {
+				sb.WriteString(" MVP")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("```")
+	return sb.String()
+}
+
+func modalTextValue(data discordgo.ModalSubmitInteractionData, inputID string) string {
+	for _, row := range data.Components {
+		actionsRow, ok := row.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, component := range actionsRow.Components {
+			if input, ok := component.(*discordgo.TextInput); ok && input.CustomID == inputID {
+				return input.Value
+			}
+		}
+	}
+	return ""
+}
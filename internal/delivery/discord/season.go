@@ -0,0 +1,86 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"valhalla/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleSeason lists seasons for everyone, starting and ending them is left to admins
+func (b *Bot) handleSeason(s *discordgo.Session, i *discordgo.Interaction) {
+	sub := i.ApplicationCommandData().Options[0]
+	adminID := interactionUser(i).ID
+	if sub.Name != "list" && !b.isAdmin(adminID) {
+		b.respondMessage(s, i, "У вас нет прав.", true)
+		return
+	}
+
+	switch sub.Name {
+	case "start":
+		var name string
+		for _, opt := range sub.Options {
+			if opt.Name == "name" {
+				name = opt.StringValue()
+			}
+		}
+		started, closed, err := b.services.MatchService.StartSeason(name, adminID)
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.respondMessage(s, i, formatSeasonStarted(started, closed), false)
+	case "end":
+		season, err := b.services.MatchService.EndSeason(adminID)
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		b.respondMessage(s, i, fmt.Sprintf("🏁 Сезон **%s** закрыт, итоги сохранены: `/top season:%s`.", season.Name, season.Name), false)
+	case "list":
+		seasons, err := b.services.MatchService.GetSeasons()
+		if err != nil {
+			b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+			return
+		}
+		if len(seasons) == 0 {
+			b.respondMessage(s, i, "Сезонов пока нет.", true)
+			return
+		}
+		s.InteractionRespond(i, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{
+				Title:       "📅 Сезоны",
+				Description: formatSeasons(seasons),
+				Color:       colorBlue,
+			}}},
+		})
+	}
+}
+
+func formatSeasonStarted(started, closed *models.Season) string {
+	text := fmt.Sprintf("🚀 Начат сезон **%s**.", started.Name)
+	if closed != nil {
+		text = fmt.Sprintf("🏁 Сезон **%s** закрыт, итоги сохранены.\n", closed.Name) + text
+	}
+	return text
+}
+
+func formatSeasons(seasons []models.Season) string {
+	var sb strings.Builder
+	for _, season := range seasons {
+		period := season.StartedAt.Format("02.01.2006") + " — "
+		if season.EndedAt != nil {
+			period += season.EndedAt.Format("02.01.2006")
+		} else {
+			period += "сейчас"
+		}
+		icon := "📦"
+		if season.Status == models.SeasonActive {
+			icon = "🟢"
+		}
+		sb.WriteString(fmt.Sprintf("%s **%s** | %s\n", icon, season.Name, period))
+	}
+	return truncateLabel(sb.String(), embedDescriptionMax)
+}
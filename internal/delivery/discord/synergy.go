@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"valhalla/internal/application"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handlePair answers /versus and /duo, both take two player IDs and an optional season
+func (b *Bot) handlePair(s *discordgo.Session, i *discordgo.Interaction, together bool) {
+	var a, other int
+	var season string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "player":
+			a = int(opt.IntValue())
+		case "other":
+			other = int(opt.IntValue())
+		case "season":
+			season = opt.StringValue()
+		}
+	}
+
+	game := b.gameOf(i)
+	getRecord := b.services.MatchService.GetVersus
+	if together {
+		getRecord = b.services.MatchService.GetDuo
+	}
+	record, err := getRecord(game, season, a, other)
+	if err != nil {
+		b.respondMessage(s, i, "Ошибка: "+err.Error(), true)
+		return
+	}
+
+	title := fmt.Sprintf("⚔️ %s против %s", record.Player, record.Other)
+	empty := "Эти игроки ещё не встречались в разных командах."
+	if together {
+		title = fmt.Sprintf("🤝 %s и %s в одной команде", record.Player, record.Other)
+		empty = "Эти игроки ещё не играли вместе."
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:  title,
+		Color:  getColorByWinRate(record.WinRate()),
+		Footer: &discordgo.MessageEmbedFooter{Text: valueOrDefault(season, "текущий сезон") + " • " + game.Name},
+	}
+	if record.Matches == 0 {
+		embed.Description = empty
+		embed.Color = colorGray
+	} else {
+		embed.Description = formatPairRecord(record, together)
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{Name: "Последние матчи", Value: formatRecentPairMatches(record), Inline: false},
+		}
+	}
+
+	s.InteractionRespond(i, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}},
+	})
+}
+
+func formatPairRecord(r *application.PairRecord, together bool) string {
+	if together {
+		return fmt.Sprintf("Матчей вместе: **%d**\n✅ Побед: %d | ❌ Поражений: %d | WR: `%.0f%%`",
+			r.Matches, r.Wins, r.Losses, r.WinRate())
+	}
+	return fmt.Sprintf("Встреч: **%d**\n**%s** %d : %d **%s** | WR %s: `%.0f%%`",
+		r.Matches, r.Player, r.Wins, r.Losses, r.Other, r.Player, r.WinRate())
+}
+
+func formatRecentPairMatches(r *application.PairRecord) string {
+	parts := make([]string, 0, len(r.RecentIDs))
+	for idx, id := range r.RecentIDs {
+		mark := "❌"
+		if r.RecentWon[idx] {
+			mark = "✅"
+		}
+		parts = append(parts, fmt.Sprintf("%s #%d", mark, id))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatTeammates lists teammates with the record of the shared matches, one per line
+func formatTeammates(records []application.PairRecord) string {
+	var lines []string
+	for _, r := range records {
+		lines = append(lines, fmt.Sprintf("%s — `%.0f%%` (%d/%d)", r.Other, r.WinRate(), r.Wins, r.Matches))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -15,9 +15,10 @@ type Bot struct {
 	profileLinkService application.ProfileLinkService
 	logger             application.Logger
 	adminIDs           map[int64]struct{}
+	roles              []string
 }
 
-func NewBot(token string, adminIDs []int64, service application.TelegramService, profileLinkService application.ProfileLinkService, logger application.Logger) (*Bot, error) {
+func NewBot(token string, adminIDs []int64, roles []string, service application.TelegramService, profileLinkService application.ProfileLinkService, logger application.Logger) (*Bot, error) {
 	bot, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create telegram bot: %w", err)
@@ -36,6 +37,7 @@ func NewBot(token string, adminIDs []int64, service application.TelegramService,
 		profileLinkService: profileLinkService,
 		logger:             logger,
 		adminIDs:           admins,
+		roles:              roles,
 	}, nil
 }
 
@@ -2,6 +2,8 @@ package telegram
 
 import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+const roleButtonsPerRow = 3
+
 func (b *Bot) isAdmin(id int64) bool {
 	_, ok := b.adminIDs[id]
 	return ok
@@ -24,24 +26,7 @@ func (b *Bot) sendMessage(chatID int64, text string, kbType string) {
 			),
 		)
 	case "role":
-		msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Gold"),
-				tgbotapi.NewKeyboardButton("Exp"),
-				tgbotapi.NewKeyboardButton("Mid"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Roam"),
-				tgbotapi.NewKeyboardButton("Jungle"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Замена"),
-				tgbotapi.NewKeyboardButton("Любая"),
-			),
-			tgbotapi.NewKeyboardButtonRow(
-				tgbotapi.NewKeyboardButton("Отмена"),
-			),
-		)
+		msg.ReplyMarkup = b.roleKeyboard()
 	case "cancel":
 		msg.ReplyMarkup = tgbotapi.NewReplyKeyboard(
 			tgbotapi.NewKeyboardButtonRow(
@@ -79,3 +64,29 @@ func valueOrDefault(val, def string) string {
 	}
 	return val
 }
+
+// roleKeyboard lists the roles of the tournament game, three per row
+func (b *Bot) roleKeyboard() tgbotapi.ReplyKeyboardMarkup {
+	var rows [][]tgbotapi.KeyboardButton
+	for start := 0; start < len(b.roles); start += roleButtonsPerRow {
+		end := start + roleButtonsPerRow
+		if end > len(b.roles) {
+			end = len(b.roles)
+		}
+		var row []tgbotapi.KeyboardButton
+		for _, role := range b.roles[start:end] {
+			row = append(row, tgbotapi.NewKeyboardButton(role))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows,
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("Замена"),
+			tgbotapi.NewKeyboardButton("Любая"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("Отмена"),
+		),
+	)
+	return tgbotapi.NewReplyKeyboard(rows...)
+}
@@ -0,0 +1,39 @@
+package games
+
+const Dota2ID = "dota2"
+
+var Dota2 = &Profile{
+	ID:             Dota2ID,
+	Name:           "Dota 2",
+	PlayersPerTeam: 5,
+	Roles:          []string{"Carry", "Mid", "Offlane", "Soft Support", "Hard Support"},
+	Stats:          []StatField{StatGold, StatHeroDamage, StatTurretDamage},
+	Prompt:         dota2Prompt,
+	MaxKills:       80,
+	MaxDeaths:      50,
+	MaxAssists:     90,
+	KillWeight:     1,
+	AssistWeight:   1,
+}
+
+const dota2Prompt = `Analyze this Dota 2 post-game scoreboard screenshot.
+    Extract data for ALL 10 players visible in the match results.
+    
+` + promptNameRules + `    RULES FOR HEROES AND NUMBERS:
+    - The hero is identified by the portrait next to the player name, use the official English hero name
+    - Use net worth as "gold", hero damage as "hero_damage" and building damage as "turret_damage"
+    - Values may be shortened (e.g. "12.3k") - convert them to full integers (12300)
+    - Dota 2 has no damage taken, teamfight participation or medals - use 0 and ""
+    - If a value is not shown on the screenshot, use 0 (or "" for strings)
+    
+    RULES FOR TEAMS AND MATCH INFO:
+    - The Radiant team is "blue", the Dire team is "red"
+    - The winner is shown in the banner at the top ("RADIANT VICTORY" or "DIRE VICTORY")
+    - The team kill scores are shown next to the team names
+    - The match duration is shown near the score, keep it as "MM:SS" (use total minutes for games over an hour, e.g. "72:15")
+    - The game mode (e.g. "All Pick", "Captains Mode", "Turbo") and the match end date/time may be shown in the header
+    
+    For each player extract: player_name, team side, result (WIN or LOSE), kills, deaths, assists,
+    hero, net worth, hero damage and building damage.
+    
+` + promptOutputFormat
@@ -0,0 +1,108 @@
+package games
+
+import (
+	"sort"
+	"strings"
+)
+
+// StatField is an optional per-player scoreboard value, games show different subsets of them
+type StatField string
+
+const (
+	StatGold         StatField = "gold"
+	StatHeroDamage   StatField = "hero_damage"
+	StatDamageTaken  StatField = "damage_taken"
+	StatTurretDamage StatField = "turret_damage"
+	StatTeamfight    StatField = "teamfight_pct"
+	StatMedal        StatField = "medal"
+)
+
+const (
+	DefaultID = MLBBID
+
+	// KDA divides by at least one death so deathless games stay finite
+	minDeathsForKDA = 1
+)
+
+// Profile describes everything game specific: how the scoreboard is read, validated and scored
+type Profile struct {
+	ID             string
+	Name           string
+	PlayersPerTeam int
+	Roles          []string
+	Stats          []StatField
+
+	// Prompt is sent to the vision model together with the screenshot
+	Prompt string
+
+	// Sanity limits for a single match, parses above them are sent back to the model
+	MaxKills   int
+	MaxDeaths  int
+	MaxAssists int
+
+	// KDA = (KillWeight*K + AssistWeight*A) / max(D, 1)
+	KillWeight   float64
+	AssistWeight float64
+}
+
+func (p *Profile) PlayersPerMatch() int {
+	return p.PlayersPerTeam * 2
+}
+
+func (p *Profile) HasStat(field StatField) bool {
+	for _, f := range p.Stats {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Profile) KDA(kills, deaths, assists int) float64 {
+	if deaths < minDeathsForKDA {
+		deaths = minDeathsForKDA
+	}
+	return (p.KillWeight*float64(kills) + p.AssistWeight*float64(assists)) / float64(deaths)
+}
+
+var registry = map[string]*Profile{
+	MLBB.ID:         MLBB,
+	HonorOfKings.ID: HonorOfKings,
+	Dota2.ID:        Dota2,
+}
+
+// Get looks a profile up by ID, case-insensitively
+func Get(id string) (*Profile, bool) {
+	p, ok := registry[strings.ToLower(strings.TrimSpace(id))]
+	return p, ok
+}
+
+// Resolve returns the profile of a stored match, matches saved before games were introduced are MLBB
+func Resolve(id string) *Profile {
+	if p, ok := Get(id); ok {
+		return p
+	}
+	return Default()
+}
+
+func Default() *Profile {
+	return registry[DefaultID]
+}
+
+// All returns the known profiles sorted by ID
+func All() []*Profile {
+	list := make([]*Profile, 0, len(registry))
+	for _, p := range registry {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func IDs() []string {
+	ids := make([]string, 0, len(registry))
+	for _, p := range All() {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
@@ -0,0 +1,38 @@
+package games
+
+const HonorOfKingsID = "hok"
+
+var HonorOfKings = &Profile{
+	ID:             HonorOfKingsID,
+	Name:           "Honor of Kings",
+	PlayersPerTeam: 5,
+	Roles:          []string{"Clash", "Mid", "Farm", "Roam", "Jungle"},
+	Stats:          []StatField{StatGold, StatHeroDamage, StatDamageTaken, StatTeamfight, StatMedal},
+	Prompt:         hokPrompt,
+	MaxKills:       60,
+	MaxDeaths:      40,
+	MaxAssists:     60,
+	KillWeight:     1,
+	AssistWeight:   1,
+}
+
+const hokPrompt = `Analyze this MOBA (Honor of Kings) scoreboard screenshot.
+    Extract data for ALL 10 players visible in the match results.
+    
+` + promptNameRules + `    RULES FOR HEROES AND NUMBERS:
+    - The hero is identified by the portrait next to the player name, use the official English hero name of the global version
+    - Gold and damage values may be shortened (e.g. "12.3k") - convert them to full integers (12300)
+    - The rating next to the hero (e.g. "9.8") is not a stat, ignore it
+    - If a value is not shown on the screenshot, use 0 (or "" for strings)
+    
+    RULES FOR TEAMS AND MATCH INFO:
+    - The left team is "blue", the right team is "red"
+    - The team kill scores are shown at the top center of the scoreboard
+    - The match duration is shown near the score, keep it as "MM:SS"
+    - The game mode (e.g. "Ranked", "Standard", "Custom") and the match end date/time may be shown in a corner
+    
+    For each player extract: player_name, team side, result (WIN or LOSE), kills, deaths, assists,
+    hero, gold, hero damage, damage taken, teamfight participation and medal.
+    Honor of Kings marks the best winner "MVP" and the best loser "SVP" - use "MVP" for both and "" for everyone else.
+    
+` + promptOutputFormat
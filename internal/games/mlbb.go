@@ -0,0 +1,36 @@
+package games
+
+const MLBBID = "mlbb"
+
+var MLBB = &Profile{
+	ID:             MLBBID,
+	Name:           "Mobile Legends: Bang Bang",
+	PlayersPerTeam: 5,
+	Roles:          []string{"Gold", "Exp", "Mid", "Roam", "Jungle"},
+	Stats:          []StatField{StatGold, StatHeroDamage, StatDamageTaken, StatTurretDamage, StatTeamfight, StatMedal},
+	Prompt:         mlbbPrompt,
+	MaxKills:       60,
+	MaxDeaths:      40,
+	MaxAssists:     60,
+	KillWeight:     1,
+	AssistWeight:   1,
+}
+
+const mlbbPrompt = `Analyze this MOBA (Mobile Legends) scoreboard screenshot.
+    Extract data for ALL 10 players visible in the match results.
+    
+` + promptNameRules + `    RULES FOR HEROES AND NUMBERS:
+    - The hero is identified by the portrait next to the player name, use the official English hero name
+    - Gold and damage values may be shortened (e.g. "12.3k") - convert them to full integers (12300)
+    - If a value is not shown on the screenshot, use 0 (or "" for strings)
+    
+    RULES FOR TEAMS AND MATCH INFO:
+    - The left team is "blue", the right team is "red"
+    - The team kill scores are shown at the top center of the scoreboard
+    - The match duration is shown near the score, keep it as "MM:SS"
+    - The game mode (e.g. "Ranked", "Classic", "Brawl", "Custom") and the match end date/time may be shown in a corner
+    
+    For each player extract: player_name, team side, result (WIN or LOSE), kills, deaths, assists,
+    hero, gold, hero damage, damage taken, turret damage, teamfight participation and medal.
+    
+` + promptOutputFormat
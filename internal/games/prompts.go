@@ -0,0 +1,45 @@
+package games
+
+// Prompt sections shared by every game, the game profiles add the intro, hero, team and field rules around them
+
+const promptNameRules = `    CRITICAL RULES FOR PLAYER NAMES:
+    - Extract player names EXACTLY as shown, character by character
+    - DO NOT add or remove any characters from the name
+    - DO NOT confuse similar characters (n vs m, l vs I, 0 vs O)
+    - If a name has special characters (icons, flags, symbols), include them only if clearly readable
+    - If a name is partially obscured, extract only the visible portion
+    - Names must be CONSISTENT - the same player should have the exact same name
+    - Return the name twice: "raw_name" exactly as displayed including decorative symbols,
+      and "player_name" with decorative icons and symbols removed
+    
+    CONFIDENCE:
+    - For every player rate from 0.0 to 1.0 how sure you are that you read it correctly:
+      "name_confidence" for every character of the name, "stats_confidence" for kills, deaths and assists
+    - Use low values (below 0.7) for blurred, cut off, overlapped or tiny text - DO NOT guess with high confidence
+    
+`
+
+const promptOutputFormat = `    Return a JSON object with two keys, "match" and "players".
+    "match" is an object with these exact keys:
+    "blue_score" (int - blue team kills), 
+    "red_score" (int - red team kills), 
+    "duration" (string - "MM:SS" or "" if not shown), 
+    "game_mode" (string - or "" if not shown), 
+    "ended_at" (string - match end time as "YYYY-MM-DD HH:MM" or "" if not shown).
+    "players" is an array of objects with these exact keys:
+    "raw_name" (string - exact name as displayed, including symbols), 
+    "player_name" (string - the name without decorative symbols), 
+    "team" (string - "blue" or "red"), 
+    "result" (string - must be "WIN" or "LOSE"), 
+    "kills" (int), 
+    "deaths" (int), 
+    "assists" (int),
+    "champion" (string - hero name),
+    "gold" (int - total gold earned),
+    "hero_damage" (int - damage dealt to heroes),
+    "damage_taken" (int),
+    "turret_damage" (int),
+    "teamfight_pct" (number - teamfight participation percent, 0-100),
+    "medal" (string - "MVP", "GOLD", "SILVER", "BRONZE" or "" if no medal is shown),
+    "name_confidence" (number - 0.0 to 1.0),
+    "stats_confidence" (number - 0.0 to 1.0).`
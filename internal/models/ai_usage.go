@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+const (
+	AIOperationParse   = "parse"
+	AIOperationReparse = "reparse"
+
+	AIOutcomeSuccess = "success"
+	AIOutcomeFailure = "failure"
+	AIOutcomeBlocked = "blocked"
+)
+
+// TokenUsage is what the model reported for one call, local providers leave it zero
+type TokenUsage struct {
+	PromptTokens   int `json:"prompt_tokens"`
+	ResponseTokens int `json:"response_tokens"`
+	TotalTokens    int `json:"total_tokens"`
+}
+
+// AIUsage records one scoreboard provider call, or a call refused because a budget ran out
+type AIUsage struct {
+	ID        int    `json:"id"`
+	Operation string `json:"operation"`
+	Outcome   string `json:"outcome"`
+	Game      string `json:"game"`
+	Platform  string `json:"platform"`
+	GuildID   string `json:"guild_id"`
+	UserID    string `json:"user_id"`
+	UserName  string `json:"user_name"`
+	TokenUsage
+	Latency   time.Duration `json:"latency"`
+	Error     string        `json:"error"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// AIUsageFilter narrows usage totals to calls since a moment, optionally of one guild or one user
+type AIUsageFilter struct {
+	Since    time.Time
+	GuildID  string
+	Platform string
+	UserID   string
+}
+
+// AIUsageTotals sums AI calls. Calls counts every request sent to the provider, failed ones included,
+// Blocked counts the requests refused by a budget
+type AIUsageTotals struct {
+	Calls    int `json:"calls"`
+	Failures int `json:"failures"`
+	Blocked  int `json:"blocked"`
+	TokenUsage
+	AvgLatency time.Duration `json:"avg_latency"`
+}
+
+// AIUserUsage is the usage of one requester
+type AIUserUsage struct {
+	Platform string `json:"platform"`
+	UserID   string `json:"user_id"`
+	UserName string `json:"user_name"`
+	AIUsageTotals
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+const (
+	GameScopeGuild   = "guild"
+	GameScopeChannel = "channel"
+)
+
+// GameSelection binds a Discord server or channel to a game profile, a channel selection wins over the server one
+type GameSelection struct {
+	Scope      string    `json:"scope"`
+	ScopeID    string    `json:"scope_id"`
+	Game       string    `json:"game"`
+	SelectedBy string    `json:"selected_by"`
+	SelectedAt time.Time `json:"selected_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	JobStatusQueued  = "queued"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// IngestionJob is a persisted request to download and parse one uploaded screenshot
+type IngestionJob struct {
+	ID     int         `json:"id"`
+	Status string      `json:"status"`
+	Source MatchSource `json:"source"`
+
+	// AttachmentIndex is the 1-based position of the screenshot in the upload message or the backfill batch
+	AttachmentIndex int `json:"attachment_index"`
+
+	// ReplyChannelID and ReplyMessageID point at the bot message that shows the upload progress
+	ReplyChannelID string `json:"reply_channel_id"`
+	ReplyMessageID string `json:"reply_message_id"`
+
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"max_attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error"`
+	MatchIDs      []int      `json:"match_ids"`
+	Duplicates    int        `json:"duplicates"`
+	Errors        []string   `json:"errors"`
+	CreatedAt     time.Time  `json:"created_at"`
+	FinishedAt    *time.Time `json:"finished_at"`
+}
+
+func (j *IngestionJob) Finished() bool {
+	return j.Status == JobStatusDone || j.Status == JobStatusFailed
+}
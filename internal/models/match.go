@@ -2,24 +2,123 @@ package models
 
 import "time"
 
+const (
+	MatchStatusPending  = "pending"
+	MatchStatusApproved = "approved"
+	MatchStatusRejected = "rejected"
+
+	// ReviewerAuto marks matches approved by the confidence policy instead of an admin
+	ReviewerAuto = "auto"
+)
+
 type Match struct {
-	ID             int            `json:"id"`
-	FileHash       string         `json:"file_hash"`
-	MatchSignature string         `json:"match_signature"`
-	CreatedAt      time.Time      `json:"created_at"`
-	Players        []PlayerResult `json:"players"`
+	ID                  int            `json:"id"`
+	Game                string         `json:"game"`
+	FileHash            string         `json:"file_hash"`
+	SourceHash          string         `json:"source_hash"`
+	RegionIndex         int            `json:"region_index"`
+	MatchSignature      string         `json:"match_signature"`
+	PerceptualHash      uint64         `json:"perceptual_hash"`
+	PossibleDuplicateOf int            `json:"possible_duplicate_of"`
+	BlueScore           int            `json:"blue_score"`
+	RedScore            int            `json:"red_score"`
+	DurationSec         int            `json:"duration_sec"`
+	GameMode            string         `json:"game_mode"`
+	Manual              bool           `json:"manual"` // entered by an admin with /add_match, there is no screenshot
+	PlayedAt            *time.Time     `json:"played_at"`
+	Status              string         `json:"status"`
+	ReviewedBy          string         `json:"reviewed_by"`
+	ReviewNote          string         `json:"review_note"`
+	Source              MatchSource    `json:"source"`
+	CreatedAt           time.Time      `json:"created_at"`
+	Players             []PlayerResult `json:"players"`
+}
+
+// Date returns the in-game end time when it was read from the scoreboard, otherwise the upload time
+func (m *Match) Date() time.Time {
+	if m.PlayedAt != nil {
+		return *m.PlayedAt
+	}
+	return m.CreatedAt
+}
+
+const (
+	PlatformDiscord  = "discord"
+	PlatformTelegram = "telegram"
+)
+
+// MatchSource describes who uploaded the screenshot a match was parsed from and where
+type MatchSource struct {
+	Platform      string `json:"platform"`
+	SubmitterID   string `json:"submitter_id"`
+	SubmitterName string `json:"submitter_name"`
+	GuildID       string `json:"guild_id"`
+	ChannelID     string `json:"channel_id"`
+	MessageID     string `json:"message_id"`
+	AttachmentURL string `json:"attachment_url"`
+
+	// PostedAt is when the screenshot was posted, it dates matches whose scoreboard shows no end time
+	PostedAt time.Time `json:"posted_at"`
+}
+
+// SubmitterStats summarizes the uploads of one user on one platform
+type SubmitterStats struct {
+	Platform      string    `json:"platform"`
+	SubmitterID   string    `json:"submitter_id"`
+	SubmitterName string    `json:"submitter_name"`
+	Total         int       `json:"total"`
+	Approved      int       `json:"approved"`
+	Pending       int       `json:"pending"`
+	Rejected      int       `json:"rejected"`
+	Deleted       int       `json:"deleted"`
+	LastUpload    time.Time `json:"last_upload"`
 }
 
+const (
+	TeamBlue = "blue"
+	TeamRed  = "red"
+)
+
+const (
+	MedalMVP    = "MVP"
+	MedalGold   = "GOLD"
+	MedalSilver = "SILVER"
+	MedalBronze = "BRONZE"
+)
+
 type PlayerResult struct {
-	ID         int    `json:"id"`
-	MatchID    int    `json:"match_id"`
-	PlayerID   int    `json:"player_id"`
-	PlayerName string `json:"player_name"`
-	Result     string `json:"result"`
-	Kills      int    `json:"kills"`
-	Deaths     int    `json:"deaths"`
-	Assists    int    `json:"assists"`
-	Champion   string `json:"champion"`
+	ID              int               `json:"id"`
+	MatchID         int               `json:"match_id"`
+	PlayerID        int               `json:"player_id"`
+	PlayerName      string            `json:"player_name"`
+	RawName         string            `json:"raw_name"`
+	Result          string            `json:"result"`
+	Kills           int               `json:"kills"`
+	Deaths          int               `json:"deaths"`
+	Assists         int               `json:"assists"`
+	Team            string            `json:"team"`
+	Champion        string            `json:"champion"`
+	Gold            int               `json:"gold"`
+	HeroDamage      int               `json:"hero_damage"`
+	DamageTaken     int               `json:"damage_taken"`
+	TurretDamage    int               `json:"turret_damage"`
+	TeamfightPct    float64           `json:"teamfight_pct"`
+	Medal           string            `json:"medal"`
+	NameConfidence  float64           `json:"name_confidence"`
+	StatsConfidence float64           `json:"stats_confidence"`
+	Candidates      []PlayerCandidate `json:"candidates,omitempty"`
+}
+
+// Unresolved reports that the OCR'd name matched several players and waits for a reviewer to pick one
+func (p *PlayerResult) Unresolved() bool {
+	return p.PlayerID == 0 && len(p.Candidates) > 0
+}
+
+// PlayerCandidate is an existing player an ambiguous OCR'd name may refer to
+type PlayerCandidate struct {
+	PlayerID int     `json:"player_id"`
+	Name     string  `json:"name"`
+	Score    float64 `json:"score"`
 }
 
 type Player struct {
@@ -27,3 +126,18 @@ type Player struct {
 	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+const (
+	AliasSourceManual = "manual"
+	AliasSourceFuzzy  = "fuzzy"
+)
+
+type PlayerAlias struct {
+	ID         int       `json:"id"`
+	Alias      string    `json:"alias"`
+	PlayerID   int       `json:"player_id"`
+	PlayerName string    `json:"player_name"`
+	Source     string    `json:"source"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
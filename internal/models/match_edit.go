@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	MatchEditManual  = "edit"
+	MatchEditReparse = "reparse"
+)
+
+// MatchEdit is an audit record of results changed after a match was stored
+type MatchEdit struct {
+	ID       int      `json:"id"`
+	MatchID  int      `json:"match_id"`
+	Kind     string   `json:"kind"`
+	EditedBy string   `json:"edited_by"`
+	Changes  []string `json:"changes"`
+
+	// Previous holds the results before the change in the /edit_match table format, enough to undo it by hand
+	Previous  string    `json:"previous"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// PlayerAggregate holds the summed results of a player's counted matches in one game
+type PlayerAggregate struct {
+	Game       string `json:"game"`
+	PlayerID   int    `json:"player_id"`
+	PlayerName string `json:"player_name"`
+
+	Matches int `json:"matches"`
+	Wins    int `json:"wins"`
+	Losses  int `json:"losses"`
+	Kills   int `json:"kills"`
+	Deaths  int `json:"deaths"`
+	Assists int `json:"assists"`
+
+	Gold         int            `json:"gold"`
+	HeroDamage   int            `json:"hero_damage"`
+	DamageTaken  int            `json:"damage_taken"`
+	TurretDamage int            `json:"turret_damage"`
+	TeamfightPct float64        `json:"teamfight_pct"`
+	MVPs         int            `json:"mvps"`
+	Heroes       map[string]int `json:"heroes"` // hero name -> matches played
+}
+
+// StatsFilter narrows the results aggregated into standings, zero values do not filter
+type StatsFilter struct {
+	Game string
+	From time.Time // inclusive
+	To   time.Time // exclusive
+	Hero string
+	Role string // main role of the linked profile
+}
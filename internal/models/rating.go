@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// RatingChange is the rating move of one player caused by one match
+type RatingChange struct {
+	Game     string    `json:"game"`
+	MatchID  int       `json:"match_id"`
+	PlayerID int       `json:"player_id"`
+	Before   float64   `json:"before"`
+	After    float64   `json:"after"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+func (c RatingChange) Delta() float64 {
+	return c.After - c.Before
+}
+
+// PlayerRating is the current rating of a player in one game
+type PlayerRating struct {
+	PlayerID  int     `json:"player_id"`
+	Rating    float64 `json:"rating"`
+	LastDelta float64 `json:"last_delta"`
+	Matches   int     `json:"matches"`
+}
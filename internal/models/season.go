@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+const (
+	SeasonActive = "active"
+	SeasonClosed = "closed"
+)
+
+type Season struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	StartedBy string     `json:"started_by"`
+	EndedBy   string     `json:"ended_by"`
+}
+
+// SeasonStanding is a player's final line of a closed season in one game
+type SeasonStanding struct {
+	SeasonID   int    `json:"season_id"`
+	Game       string `json:"game"`
+	PlayerID   int    `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	Rank       int    `json:"rank"`
+
+	Matches int     `json:"matches"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Kills   int     `json:"kills"`
+	Deaths  int     `json:"deaths"`
+	Assists int     `json:"assists"`
+	KDA     float64 `json:"kda"`
+	Rating  float64 `json:"rating"`
+
+	Gold         int            `json:"gold"`
+	HeroDamage   int            `json:"hero_damage"`
+	DamageTaken  int            `json:"damage_taken"`
+	TurretDamage int            `json:"turret_damage"`
+	TeamfightPct float64        `json:"teamfight_pct"`
+	MVPs         int            `json:"mvps"`
+	Heroes       map[string]int `json:"heroes"`
+}
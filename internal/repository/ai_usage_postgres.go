@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"valhalla/internal/models"
+)
+
+type AIUsagePostgres struct {
+	db *sql.DB
+}
+
+func NewAIUsagePostgres(db *sql.DB) *AIUsagePostgres {
+	return &AIUsagePostgres{db: db}
+}
+
+const aiUsageTotalsColumns = `
+	COUNT(*) FILTER (WHERE outcome <> $1),
+	COUNT(*) FILTER (WHERE outcome = $2),
+	COUNT(*) FILTER (WHERE outcome = $1),
+	COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(response_tokens), 0), COALESCE(SUM(total_tokens), 0),
+	COALESCE(AVG(latency_ms) FILTER (WHERE outcome <> $1), 0)`
+
+func (r *AIUsagePostgres) RecordAIUsage(u models.AIUsage) error {
+	_, err := r.db.Exec(`
+		INSERT INTO ai_usage (operation, outcome, game, platform, guild_id, user_id, user_name,
+		                      prompt_tokens, response_tokens, total_tokens, latency_ms, error)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''),
+		        $8, $9, $10, $11, NULLIF($12, ''))
+	`, u.Operation, u.Outcome, u.Game, u.Platform, u.GuildID, u.UserID, u.UserName,
+		u.PromptTokens, u.ResponseTokens, u.TotalTokens, u.Latency.Milliseconds(), u.Error)
+	if err != nil {
+		return fmt.Errorf("failed to record ai usage: %w", err)
+	}
+	return nil
+}
+
+func (r *AIUsagePostgres) GetAIUsageTotals(filter models.AIUsageFilter) (*models.AIUsageTotals, error) {
+	where, args := aiUsageWhere(filter)
+	totals, err := scanAIUsageTotals(r.db.QueryRow(`SELECT `+aiUsageTotalsColumns+` FROM ai_usage WHERE `+where, args...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ai usage: %w", err)
+	}
+	return totals, nil
+}
+
+// GetAIUsageByUser returns the heaviest requesters matching the filter, most calls first
+func (r *AIUsagePostgres) GetAIUsageByUser(filter models.AIUsageFilter, limit int) ([]models.AIUserUsage, error) {
+	where, args := aiUsageWhere(filter)
+	args = append(args, limit)
+	rows, err := r.db.Query(`
+		SELECT COALESCE(platform, ''), user_id, COALESCE(MAX(user_name), ''), `+aiUsageTotalsColumns+`
+		FROM ai_usage
+		WHERE user_id IS NOT NULL AND `+where+`
+		GROUP BY platform, user_id
+		ORDER BY COUNT(*) FILTER (WHERE outcome <> $1) DESC, user_id
+		LIMIT $`+fmt.Sprint(len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ai usage by user: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []models.AIUserUsage
+	for rows.Next() {
+		var u models.AIUserUsage
+		var avgLatency float64
+		t := &u.AIUsageTotals
+		if err := rows.Scan(&u.Platform, &u.UserID, &u.UserName, &t.Calls, &t.Failures, &t.Blocked,
+			&t.PromptTokens, &t.ResponseTokens, &t.TotalTokens, &avgLatency); err != nil {
+			continue
+		}
+		t.AvgLatency = time.Duration(avgLatency) * time.Millisecond
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// aiUsageWhere builds the filter condition, $1 and $2 are taken by the outcomes used in the totals
+func aiUsageWhere(filter models.AIUsageFilter) (string, []interface{}) {
+	args := []interface{}{models.AIOutcomeBlocked, models.AIOutcomeFailure, filter.Since}
+	conds := []string{"created_at >= $3"}
+	if filter.GuildID != "" {
+		args = append(args, filter.GuildID)
+		conds = append(conds, fmt.Sprintf("guild_id = $%d", len(args)))
+	}
+	if filter.UserID != "" {
+		args = append(args, filter.Platform, filter.UserID)
+		conds = append(conds, fmt.Sprintf("platform = $%d AND user_id = $%d", len(args)-1, len(args)))
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func scanAIUsageTotals(row rowScanner) (*models.AIUsageTotals, error) {
+	var t models.AIUsageTotals
+	var avgLatency float64
+	if err := row.Scan(&t.Calls, &t.Failures, &t.Blocked, &t.PromptTokens, &t.ResponseTokens, &t.TotalTokens, &avgLatency); err != nil {
+		return nil, err
+	}
+	t.AvgLatency = time.Duration(avgLatency) * time.Millisecond
+	return &t, nil
+}
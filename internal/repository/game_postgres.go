@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"valhalla/internal/models"
+)
+
+// GetGameSelection returns the game chosen for the channel, or for the server when the channel has none.
+// It returns nil when neither is set
+func (r *MatchPostgres) GetGameSelection(guildID, channelID string) (*models.GameSelection, error) {
+	var sel models.GameSelection
+	err := r.db.QueryRow(`
+		SELECT scope, scope_id, game, COALESCE(selected_by, ''), selected_at
+		FROM game_selections
+		WHERE (scope = $1 AND scope_id = $2) OR (scope = $3 AND scope_id = $4)
+		ORDER BY scope = $1 DESC
+		LIMIT 1
+	`, models.GameScopeChannel, channelID, models.GameScopeGuild, guildID).Scan(
+		&sel.Scope, &sel.ScopeID, &sel.Game, &sel.SelectedBy, &sel.SelectedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game selection: %w", err)
+	}
+	return &sel, nil
+}
+
+func (r *MatchPostgres) SetGameSelection(sel models.GameSelection) error {
+	_, err := r.db.Exec(`
+		INSERT INTO game_selections (scope, scope_id, game, selected_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		ON CONFLICT (scope, scope_id) DO UPDATE SET
+			game = EXCLUDED.game,
+			selected_by = EXCLUDED.selected_by,
+			selected_at = NOW()
+	`, sel.Scope, sel.ScopeID, sel.Game, sel.SelectedBy)
+	if err != nil {
+		return fmt.Errorf("failed to set game selection: %w", err)
+	}
+	return nil
+}
+
+func (r *MatchPostgres) DeleteGameSelection(scope, scopeID string) error {
+	res, err := r.db.Exec("DELETE FROM game_selections WHERE scope = $1 AND scope_id = $2", scope, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete game selection: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
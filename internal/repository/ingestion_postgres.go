@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"valhalla/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type IngestionPostgres struct {
+	db *sql.DB
+}
+
+func NewIngestionPostgres(db *sql.DB) *IngestionPostgres {
+	return &IngestionPostgres{db: db}
+}
+
+const jobColumns = `id, status, platform, COALESCE(submitter_id, ''), COALESCE(submitter_name, ''), COALESCE(guild_id, ''),
+	COALESCE(channel_id, ''), COALESCE(message_id, ''), attachment_url, attachment_index,
+	COALESCE(reply_channel_id, ''), COALESCE(reply_message_id, ''), attempts, max_attempts, next_attempt_at,
+	COALESCE(last_error, ''), match_ids, duplicates, errors, created_at, finished_at, posted_at`
+
+// CreateJobs queues the jobs in one transaction and fills in their IDs
+func (r *IngestionPostgres) CreateJobs(jobs []models.IngestionJob) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range jobs {
+		job := &jobs[i]
+		src := job.Source
+		err := tx.QueryRow(`
+			INSERT INTO ingestion_jobs (platform, submitter_id, submitter_name, guild_id, channel_id, message_id,
+			                            attachment_url, attachment_index, reply_channel_id, reply_message_id, max_attempts, posted_at)
+			VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''),
+			        $7, $8, NULLIF($9, ''), NULLIF($10, ''), $11, $12)
+			RETURNING id, status, next_attempt_at, created_at
+		`, src.Platform, src.SubmitterID, src.SubmitterName, src.GuildID, src.ChannelID, src.MessageID,
+			src.AttachmentURL, job.AttachmentIndex, job.ReplyChannelID, job.ReplyMessageID, job.MaxAttempts,
+			nullableTime(src.PostedAt),
+		).Scan(&job.ID, &job.Status, &job.NextAttemptAt, &job.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create ingestion job: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ClaimJob locks the next due job for a worker and counts the attempt. Jobs left running longer than lease
+// belonged to a worker that died and are taken over. It returns nil when nothing is due
+func (r *IngestionPostgres) ClaimJob(lease time.Duration) (*models.IngestionJob, error) {
+	job, err := scanJob(r.db.QueryRow(`
+		UPDATE ingestion_jobs SET status = $1, locked_at = NOW(), attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM ingestion_jobs
+			WHERE (status = $2 AND next_attempt_at <= NOW()) OR (status = $1 AND locked_at < $3)
+			ORDER BY next_attempt_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING `+jobColumns,
+		models.JobStatusRunning, models.JobStatusQueued, time.Now().Add(-lease)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim ingestion job: %w", err)
+	}
+	return job, nil
+}
+
+// RetryJob puts a job back into the queue after a transient failure. The matches already stored from
+// the screenshot are kept, so the next attempt can tell its own scoreboards from real duplicates
+func (r *IngestionPostgres) RetryJob(job models.IngestionJob) error {
+	_, err := r.db.Exec(`
+		UPDATE ingestion_jobs
+		SET status = $2, next_attempt_at = $3, last_error = $4, match_ids = $5, duplicates = $6, locked_at = NULL
+		WHERE id = $1
+	`, job.ID, models.JobStatusQueued, job.NextAttemptAt, job.LastError, intArray(job.MatchIDs), job.Duplicates)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule ingestion job: %w", err)
+	}
+	return nil
+}
+
+// FinishJob stores the outcome of a job that will not be retried
+func (r *IngestionPostgres) FinishJob(job models.IngestionJob) error {
+	_, err := r.db.Exec(`
+		UPDATE ingestion_jobs
+		SET status = $2, match_ids = $3, duplicates = $4, errors = $5, last_error = NULLIF($6, ''),
+		    locked_at = NULL, finished_at = NOW()
+		WHERE id = $1
+	`, job.ID, job.Status, intArray(job.MatchIDs), job.Duplicates, pq.StringArray(job.Errors), job.LastError)
+	if err != nil {
+		return fmt.Errorf("failed to finish ingestion job: %w", err)
+	}
+	return nil
+}
+
+func (r *IngestionPostgres) GetJobsByReply(channelID, messageID string) ([]models.IngestionJob, error) {
+	rows, err := r.db.Query(`SELECT `+jobColumns+` FROM ingestion_jobs
+		WHERE reply_channel_id = $1 AND reply_message_id = $2
+		ORDER BY attachment_index, id`, channelID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingestion jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []models.IngestionJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, nil
+}
+
+func scanJob(row rowScanner) (*models.IngestionJob, error) {
+	var job models.IngestionJob
+	var matchIDs pq.Int64Array
+	var errs pq.StringArray
+	var finishedAt, postedAt sql.NullTime
+	src := &job.Source
+	err := row.Scan(&job.ID, &job.Status, &src.Platform, &src.SubmitterID, &src.SubmitterName, &src.GuildID,
+		&src.ChannelID, &src.MessageID, &src.AttachmentURL, &job.AttachmentIndex,
+		&job.ReplyChannelID, &job.ReplyMessageID, &job.Attempts, &job.MaxAttempts, &job.NextAttemptAt,
+		&job.LastError, &matchIDs, &job.Duplicates, &errs, &job.CreatedAt, &finishedAt, &postedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range matchIDs {
+		job.MatchIDs = append(job.MatchIDs, int(id))
+	}
+	job.Errors = errs
+	job.FinishedAt = nullTimePtr(finishedAt)
+	if postedAt.Valid {
+		src.PostedAt = postedAt.Time
+	}
+	return &job, nil
+}
+
+func intArray(ids []int) interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	arr := make(pq.Int64Array, len(ids))
+	for i, id := range ids {
+		arr[i] = int64(id)
+	}
+	return arr
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+	"valhalla/internal/models"
+
+	"github.com/lib/pq"
+)
+
+func (r *MatchPostgres) AddMatchEdit(edit models.MatchEdit) error {
+	_, err := r.db.Exec(`
+		INSERT INTO match_edits (match_id, kind, edited_by, changes, previous)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+	`, edit.MatchID, edit.Kind, edit.EditedBy, pq.StringArray(edit.Changes), edit.Previous)
+	if err != nil {
+		return fmt.Errorf("failed to record match edit: %w", err)
+	}
+	return nil
+}
+
+// GetMatchEdits returns the audit trail of a match, newest first
+func (r *MatchPostgres) GetMatchEdits(matchID int) ([]models.MatchEdit, error) {
+	rows, err := r.db.Query(`
+		SELECT id, match_id, kind, edited_by, changes, COALESCE(previous, ''), created_at
+		FROM match_edits
+		WHERE match_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match edits: %w", err)
+	}
+	defer rows.Close()
+
+	var edits []models.MatchEdit
+	for rows.Next() {
+		var e models.MatchEdit
+		var changes pq.StringArray
+		if err := rows.Scan(&e.ID, &e.MatchID, &e.Kind, &e.EditedBy, &changes, &e.Previous, &e.CreatedAt); err != nil {
+			continue
+		}
+		e.Changes = changes
+		edits = append(edits, e)
+	}
+	return edits, nil
+}
@@ -2,18 +2,31 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 	"valhalla/internal/models"
+
+	"github.com/lib/pq"
 )
 
 const (
 	similarityThreshold     = 0.85
+	ambiguityThreshold      = 0.7
+	maxPlayerCandidates     = 4
+	minFuzzyNameLength      = 4
 	defaultSeasonStartYear  = 2025
 	defaultSeasonStartMonth = 1
 	defaultSeasonStartDay   = 1
 	minDeathsForKDA         = 1
+	playerResultColumns     = 19
+
+	// PostgreSQL error code of a unique index conflict
+	uniqueViolation = "23505"
 )
 
 type MatchPostgres struct {
@@ -38,6 +51,21 @@ func NewMatchPostgres(db *sql.DB) *MatchPostgres {
 		cache.LoadAll(players)
 	}
 
+	aliasRows, err := db.Query(`
+		SELECT a.normalized_alias, a.player_id FROM player_aliases a
+		JOIN players p ON p.id = a.player_id
+		WHERE p.is_deleted = FALSE`)
+	if err == nil {
+		defer aliasRows.Close()
+		for aliasRows.Next() {
+			var alias string
+			var playerID int
+			if err := aliasRows.Scan(&alias, &playerID); err == nil {
+				cache.Set(alias, playerID)
+			}
+		}
+	}
+
 	return &MatchPostgres{
 		db:          db,
 		playerCache: cache,
@@ -55,21 +83,34 @@ func (r *MatchPostgres) Create(match models.Match) (int, error) {
 		}
 	}()
 
+	status := match.Status
+	if status == "" {
+		status = models.MatchStatusPending
+	}
+
 	var matchID int
-	query := "INSERT INTO matches (file_hash, match_signature) VALUES ($1, $2) RETURNING id"
-	err = tx.QueryRow(query, match.FileHash, match.MatchSignature).Scan(&matchID)
+	src := match.Source
+	query := `INSERT INTO matches (file_hash, match_signature, status, perceptual_hash, possible_duplicate_of,
+	                               blue_score, red_score, duration_sec, game_mode, played_at,
+	                               platform, submitter_id, submitter_name, guild_id, channel_id, message_id, attachment_url,
+	                               reviewed_by, reviewed_at, review_note, source_hash, region_index, game, is_manual)
+	          VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6, $7, $8, $9, $10,
+	                  NULLIF($11, ''), NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''), NULLIF($15, ''), NULLIF($16, ''), NULLIF($17, ''),
+	                  NULLIF($18, ''), CASE WHEN $18 = '' THEN NULL ELSE NOW() END, NULLIF($19, ''), NULLIF($20, ''), $21, $22, $23)
+	          RETURNING id`
+	err = tx.QueryRow(query, match.FileHash, match.MatchSignature, status,
+		nullablePerceptualHash(match.PerceptualHash), nullableID(match.PossibleDuplicateOf),
+		match.BlueScore, match.RedScore, match.DurationSec, match.GameMode, match.PlayedAt,
+		src.Platform, src.SubmitterID, src.SubmitterName, src.GuildID, src.ChannelID, src.MessageID, src.AttachmentURL,
+		match.ReviewedBy, match.ReviewNote, match.SourceHash, match.RegionIndex, match.Game, match.Manual).Scan(&matchID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert match: %w", err)
 	}
 
 	// Collect all player IDs (using cache for fast lookups)
-	playerIDs := make([]int, len(match.Players))
-	for i, p := range match.Players {
-		playerID, err := r.EnsurePlayerExists(p.PlayerName)
-		if err != nil {
-			return 0, fmt.Errorf("failed to ensure player exists: %w", err)
-		}
-		playerIDs[i] = playerID
+	playerIDs, err := r.resolvePlayerIDs(match.Players)
+	if err != nil {
+		return 0, err
 	}
 
 	// Batch insert all player results in one query
@@ -83,24 +124,70 @@ func (r *MatchPostgres) Create(match models.Match) (int, error) {
 	return matchID, nil
 }
 
+// Exists reports whether a live match was stored from the same screenshot or with the same results.
+// Rejected matches do not count, the same screenshot may be uploaded again after a rejection.
+// Scoreboards cut out of a collage are stored under the hash of their crop, each one is checked on its own
 func (r *MatchPostgres) Exists(fileHash, matchSignature string) (bool, error) {
-	var exists bool
-	query := "SELECT EXISTS(SELECT 1 FROM matches WHERE (file_hash=$1 OR match_signature=$2) AND is_deleted = FALSE)"
-	err := r.db.QueryRow(query, fileHash, matchSignature).Scan(&exists)
+	id, err := r.findLiveMatch(fileHash, matchSignature)
+	return id != 0, err
+}
+
+// FindByFileHash returns the ID of the live match stored from the image with this hash, or 0 if there is none
+func (r *MatchPostgres) FindByFileHash(fileHash string) (int, error) {
+	return r.findLiveMatch(fileHash, "")
+}
+
+// FindBySignature returns the ID of the live match stored with the same results, or 0 if there is none
+func (r *MatchPostgres) FindBySignature(matchSignature string) (int, error) {
+	return r.findLiveMatch("", matchSignature)
+}
+
+func (r *MatchPostgres) findLiveMatch(fileHash, matchSignature string) (int, error) {
+	var id int
+	query := "SELECT id FROM matches WHERE (file_hash=$1 OR match_signature=$2) AND is_deleted = FALSE AND status <> $3 ORDER BY id LIMIT 1"
+	err := r.db.QueryRow(query, fileHash, matchSignature, models.MatchStatusRejected).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
 	if err != nil {
-		return false, fmt.Errorf("failed to check match existence: %w", err)
+		return 0, fmt.Errorf("failed to check match existence: %w", err)
 	}
-	return exists, nil
+	return id, nil
 }
 
 func (r *MatchPostgres) GetAllAfter(date time.Time) ([]models.Match, error) {
+	return r.queryMatchesWithResults("COALESCE(m.played_at, m.created_at) >= $1 AND m.status = $2", date, models.MatchStatusApproved)
+}
+
+// GetPlayerMatches returns the approved matches of a game a player took part in, played in [from, to), with
+// their full rosters. A zero to means no upper bound
+func (r *MatchPostgres) GetPlayerMatches(game string, playerID int, from, to time.Time) ([]models.Match, error) {
+	condition := `m.game = $1 AND m.status = $2 AND COALESCE(m.played_at, m.created_at) >= $3
+		AND m.id IN (SELECT match_id FROM player_results WHERE player_id = $4 AND is_deleted = FALSE)`
+	args := []interface{}{game, models.MatchStatusApproved, from, playerID}
+	if !to.IsZero() {
+		condition += " AND COALESCE(m.played_at, m.created_at) < $5"
+		args = append(args, to)
+	}
+	return r.queryMatchesWithResults(condition, args...)
+}
+
+// GetRecentMatches returns pending and approved matches created after the date, used for duplicate detection
+func (r *MatchPostgres) GetRecentMatches(since time.Time) ([]models.Match, error) {
+	return r.queryMatchesWithResults("m.created_at >= $1 AND m.status <> $2", since, models.MatchStatusRejected)
+}
+
+// queryMatchesWithResults loads live matches matching the condition together with their player results
+func (r *MatchPostgres) queryMatchesWithResults(condition string, args ...interface{}) ([]models.Match, error) {
 	query := `
-		SELECT m.id, m.created_at, pr.player_name, pr.result, pr.kills, pr.deaths, pr.assists, pr.player_id
+		SELECT m.id, m.game, m.created_at, m.played_at, pr.player_name, pr.result, pr.kills, pr.deaths, pr.assists, COALESCE(pr.player_id, 0),
+		       COALESCE(pr.team, ''), COALESCE(pr.champion, ''), COALESCE(pr.gold, 0), COALESCE(pr.hero_damage, 0),
+		       COALESCE(pr.damage_taken, 0), COALESCE(pr.turret_damage, 0), COALESCE(pr.teamfight_pct, 0), COALESCE(pr.medal, '')
 		FROM matches m
 		JOIN player_results pr ON m.id = pr.match_id
-		WHERE m.created_at >= $1 AND m.is_deleted = FALSE AND pr.is_deleted = FALSE
+		WHERE ` + condition + ` AND m.is_deleted = FALSE AND pr.is_deleted = FALSE
 	`
-	rows, err := r.db.Query(query, date)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query matches: %w", err)
 	}
@@ -109,15 +196,20 @@ func (r *MatchPostgres) GetAllAfter(date time.Time) ([]models.Match, error) {
 	matchesMap := make(map[int]*models.Match)
 	for rows.Next() {
 		var id int
+		var game string
 		var createdAt time.Time
+		var playedAt sql.NullTime
 		var pr models.PlayerResult
-		if err := rows.Scan(&id, &createdAt, &pr.PlayerName, &pr.Result, &pr.Kills, &pr.Deaths, &pr.Assists, &pr.PlayerID); err != nil {
+		if err := rows.Scan(&id, &game, &createdAt, &playedAt, &pr.PlayerName, &pr.Result, &pr.Kills, &pr.Deaths, &pr.Assists, &pr.PlayerID,
+			&pr.Team, &pr.Champion, &pr.Gold, &pr.HeroDamage, &pr.DamageTaken, &pr.TurretDamage, &pr.TeamfightPct, &pr.Medal); err != nil {
 			continue
 		}
 		if _, ok := matchesMap[id]; !ok {
 			matchesMap[id] = &models.Match{
 				ID:        id,
+				Game:      game,
 				CreatedAt: createdAt,
+				PlayedAt:  nullTimePtr(playedAt),
 				Players:   []models.PlayerResult{},
 			}
 		}
@@ -155,6 +247,10 @@ func (r *MatchPostgres) Delete(id int) error {
 func (r *MatchPostgres) Restore(id int) error {
 	query := "UPDATE matches SET is_deleted = FALSE, deleted_at = NULL WHERE id = $1 AND is_deleted = TRUE"
 	res, err := r.db.Exec(query, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("failed to restore match: the screenshot or the results were uploaded again since")
+	}
 	if err != nil {
 		return fmt.Errorf("failed to restore match: %w", err)
 	}
@@ -173,51 +269,223 @@ func (r *MatchPostgres) Restore(id int) error {
 	return nil
 }
 
-func (r *MatchPostgres) WipeAll() error {
-	_, err := r.db.Exec("UPDATE matches SET is_deleted = TRUE, deleted_at = NOW() WHERE is_deleted = FALSE")
+func (r *MatchPostgres) GetByID(id int) (*models.Match, error) {
+	m, err := scanMatch(r.db.QueryRow(`SELECT `+matchColumns+` FROM matches WHERE id = $1 AND is_deleted = FALSE`, id))
 	if err != nil {
-		return fmt.Errorf("failed to soft delete all matches: %w", err)
+		return nil, fmt.Errorf("match with ID %d not found: %w", id, err)
 	}
-	_, err = r.db.Exec("UPDATE player_results SET is_deleted = TRUE WHERE is_deleted = FALSE")
+
+	m.Players, err = r.getPlayerResults(id)
 	if err != nil {
-		return fmt.Errorf("failed to soft delete all player results: %w", err)
+		return nil, err
 	}
-	_, err = r.db.Exec("UPDATE players SET is_deleted = TRUE, deleted_at = NOW() WHERE is_deleted = FALSE")
+	return m, nil
+}
+
+// GetDeletedByID returns a deleted match without its results, enough to decide whether it may be restored
+func (r *MatchPostgres) GetDeletedByID(id int) (*models.Match, error) {
+	m, err := scanMatch(r.db.QueryRow(`SELECT `+matchColumns+` FROM matches WHERE id = $1 AND is_deleted = TRUE`, id))
 	if err != nil {
-		return fmt.Errorf("failed to soft delete all players: %w", err)
+		return nil, fmt.Errorf("deleted match with ID %d not found: %w", id, err)
 	}
+	return m, nil
+}
 
-	// Clear entire cache since all players are wiped
-	r.playerCache.Clear()
+func (r *MatchPostgres) GetByStatus(status string) ([]models.Match, error) {
+	rows, err := r.db.Query(`SELECT `+matchColumns+` FROM matches WHERE status = $1 AND is_deleted = FALSE ORDER BY created_at`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matches by status: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []models.Match
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			continue
+		}
+		matches = append(matches, *m)
+	}
+	rows.Close()
+
+	for i := range matches {
+		matches[i].Players, err = r.getPlayerResults(matches[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
 
+// FindSimilarMatch returns the ID of the closest live match whose perceptual hash differs by at most
+// maxDistance bits together with the distance, or 0 if there is none
+func (r *MatchPostgres) FindSimilarMatch(perceptualHash uint64, maxDistance int) (int, int, error) {
+	var id, distance int
+	err := r.db.QueryRow(`
+		SELECT id, bit_count((perceptual_hash # $1)::bit(64)) AS distance FROM matches
+		WHERE perceptual_hash IS NOT NULL AND is_deleted = FALSE AND status <> $3
+		  AND bit_count((perceptual_hash # $1)::bit(64)) <= $2
+		ORDER BY distance, id
+		LIMIT 1
+	`, int64(perceptualHash), maxDistance, models.MatchStatusRejected).Scan(&id, &distance)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to search similar matches: %w", err)
+	}
+	return id, distance, nil
+}
+
+func (r *MatchPostgres) SetStatus(id int, status, reviewedBy string) error {
+	res, err := r.db.Exec(`
+		UPDATE matches SET status = $2, reviewed_by = $3, reviewed_at = NOW()
+		WHERE id = $1 AND is_deleted = FALSE
+	`, id, status, reviewedBy)
+	if err != nil {
+		return fmt.Errorf("failed to update match status: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
 	return nil
 }
 
-func (r *MatchPostgres) SetSeasonStartDate(date time.Time) error {
-	_, err := r.db.Exec(`
-       INSERT INTO bot_settings (key, value) VALUES ('season_start_date', $1)
-       ON CONFLICT (key) DO UPDATE SET value = $1
-    `, date.Format(time.RFC3339))
+// ReplaceResults overwrites all player results of a match, used when a reviewer corrects the parsed table
+func (r *MatchPostgres) ReplaceResults(matchID int, matchSignature string, players []models.PlayerResult) error {
+	playerIDs, err := r.resolvePlayerIDs(players)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to set season start date: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.replaceResults(tx, matchID, matchSignature, players, playerIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	return nil
 }
 
-func (r *MatchPostgres) GetSeasonStartDate() (time.Time, error) {
-	var val string
-	err := r.db.QueryRow("SELECT value FROM bot_settings WHERE key = 'season_start_date'").Scan(&val)
-	if err == sql.ErrNoRows {
-		return time.Date(defaultSeasonStartYear, defaultSeasonStartMonth, defaultSeasonStartDay, 0, 0, 0, 0, time.UTC), nil
+// ReplaceParse overwrites the match level values read from the scoreboard together with all player results,
+// used when a match is re-parsed from its screenshot
+func (r *MatchPostgres) ReplaceParse(id int, match models.Match) error {
+	playerIDs, err := r.resolvePlayerIDs(match.Players)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE matches SET blue_score = $2, red_score = $3, duration_sec = $4, game_mode = $5, played_at = $6
+		WHERE id = $1
+	`, id, match.BlueScore, match.RedScore, match.DurationSec, match.GameMode, match.PlayedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update match metadata: %w", err)
+	}
+	if err := r.replaceResults(tx, id, match.MatchSignature, match.Players, playerIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *MatchPostgres) replaceResults(tx *sql.Tx, matchID int, matchSignature string, players []models.PlayerResult, playerIDs []int) error {
+	if _, err := tx.Exec("UPDATE matches SET match_signature = $2 WHERE id = $1", matchID, matchSignature); err != nil {
+		return fmt.Errorf("failed to update match signature: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM player_results WHERE match_id = $1", matchID); err != nil {
+		return fmt.Errorf("failed to delete old player results: %w", err)
+	}
+	if err := r.batchInsertPlayerResults(tx, matchID, players, playerIDs); err != nil {
+		return fmt.Errorf("failed to insert player results: %w", err)
+	}
+	return nil
+}
+
+func (r *MatchPostgres) getPlayerResults(matchID int) ([]models.PlayerResult, error) {
+	rows, err := r.db.Query(`
+		SELECT id, match_id, COALESCE(player_id, 0), player_name, result, kills, deaths, assists, COALESCE(team, ''),
+		       COALESCE(champion, ''), COALESCE(gold, 0), COALESCE(hero_damage, 0), COALESCE(damage_taken, 0),
+		       COALESCE(turret_damage, 0), COALESCE(teamfight_pct, 0), COALESCE(medal, ''), candidate_ids,
+		       COALESCE(raw_name, ''), COALESCE(name_confidence, 0), COALESCE(stats_confidence, 0)
+		FROM player_results
+		WHERE match_id = $1 AND is_deleted = FALSE
+		ORDER BY id
+	`, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.PlayerResult
+	var candidateLists []pq.Int64Array
+	for rows.Next() {
+		var pr models.PlayerResult
+		var candidates pq.Int64Array
+		if err := rows.Scan(&pr.ID, &pr.MatchID, &pr.PlayerID, &pr.PlayerName, &pr.Result, &pr.Kills, &pr.Deaths, &pr.Assists, &pr.Team,
+			&pr.Champion, &pr.Gold, &pr.HeroDamage, &pr.DamageTaken, &pr.TurretDamage, &pr.TeamfightPct, &pr.Medal, &candidates,
+			&pr.RawName, &pr.NameConfidence, &pr.StatsConfidence); err != nil {
+			continue
+		}
+		results = append(results, pr)
+		candidateLists = append(candidateLists, candidates)
+	}
+	rows.Close()
+
+	// Unresolved results carry the candidate players a reviewer chooses from
+	for i := range results {
+		if results[i].PlayerID != 0 {
+			continue
+		}
+		for _, id := range candidateLists[i] {
+			name, err := r.GetPlayerNameByID(int(id))
+			if err != nil {
+				continue
+			}
+			results[i].Candidates = append(results[i].Candidates, models.PlayerCandidate{PlayerID: int(id), Name: name})
+		}
 	}
+	return results, nil
+}
+
+func (r *MatchPostgres) WipeAll() error {
+	_, err := r.db.Exec("UPDATE matches SET is_deleted = TRUE, deleted_at = NOW() WHERE is_deleted = FALSE")
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to get season start date: %w", err)
+		return fmt.Errorf("failed to soft delete all matches: %w", err)
 	}
-	parsed, err := time.Parse(time.RFC3339, val)
+	_, err = r.db.Exec("UPDATE player_results SET is_deleted = TRUE WHERE is_deleted = FALSE")
 	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to parse season start date: %w", err)
+		return fmt.Errorf("failed to soft delete all player results: %w", err)
 	}
-	return parsed, nil
+	_, err = r.db.Exec("UPDATE players SET is_deleted = TRUE, deleted_at = NOW() WHERE is_deleted = FALSE")
+	if err != nil {
+		return fmt.Errorf("failed to soft delete all players: %w", err)
+	}
+
+	// Clear entire cache since all players are wiped
+	r.playerCache.Clear()
+
+	return nil
 }
 
 func (r *MatchPostgres) SetPlayerResetDate(playerName string, date time.Time) error {
@@ -251,14 +519,15 @@ func (r *MatchPostgres) GetPlayerResetDates() (map[string]time.Time, error) {
 
 func (r *MatchPostgres) GetHistory(playerID int, limit int) ([]models.Match, error) {
 	query := `
-		SELECT m.id, m.created_at, pr.result, pr.kills, pr.deaths, pr.assists, pr.player_name
+		SELECT m.id, m.created_at, m.played_at, pr.result, pr.kills, pr.deaths, pr.assists, pr.player_name,
+		       COALESCE(pr.champion, ''), COALESCE(pr.gold, 0), COALESCE(pr.medal, '')
 		FROM matches m
 		JOIN player_results pr ON m.id = pr.match_id
-		WHERE pr.player_id = $1 AND m.is_deleted = FALSE AND pr.is_deleted = FALSE
-		ORDER BY m.created_at DESC
+		WHERE pr.player_id = $1 AND m.status = $3 AND m.is_deleted = FALSE AND pr.is_deleted = FALSE
+		ORDER BY COALESCE(m.played_at, m.created_at) DESC
 		LIMIT $2
 	`
-	rows, err := r.db.Query(query, playerID, limit)
+	rows, err := r.db.Query(query, playerID, limit, models.MatchStatusApproved)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get player history: %w", err)
 	}
@@ -267,11 +536,14 @@ func (r *MatchPostgres) GetHistory(playerID int, limit int) ([]models.Match, err
 	var matches []models.Match
 	for rows.Next() {
 		var m models.Match
+		var playedAt sql.NullTime
 		var pr models.PlayerResult
-		err := rows.Scan(&m.ID, &m.CreatedAt, &pr.Result, &pr.Kills, &pr.Deaths, &pr.Assists, &pr.PlayerName)
+		err := rows.Scan(&m.ID, &m.CreatedAt, &playedAt, &pr.Result, &pr.Kills, &pr.Deaths, &pr.Assists, &pr.PlayerName,
+			&pr.Champion, &pr.Gold, &pr.Medal)
 		if err != nil {
 			continue
 		}
+		m.PlayedAt = nullTimePtr(playedAt)
 		pr.PlayerID = playerID
 		m.Players = []models.PlayerResult{pr}
 		matches = append(matches, m)
@@ -279,37 +551,88 @@ func (r *MatchPostgres) GetHistory(playerID int, limit int) ([]models.Match, err
 	return matches, nil
 }
 
+// EnsurePlayerExists resolves a name without a reviewer: an ambiguous name goes to the closest
+// confident candidate, an unknown one becomes a new player
 func (r *MatchPostgres) EnsurePlayerExists(name string) (int, error) {
+	id, candidates, err := r.ResolvePlayerName(name)
+	if err != nil {
+		return 0, err
+	}
+	if id != 0 {
+		return id, nil
+	}
+	if len(candidates) > 0 && candidates[0].Score > similarityThreshold {
+		return candidates[0].PlayerID, nil
+	}
+	return r.CreatePlayer(name)
+}
+
+// ResolvePlayerName looks a name up without creating anything. It returns the player ID when the name is known
+// or confidently matches exactly one player, otherwise the close candidates sorted by similarity (possibly none)
+func (r *MatchPostgres) ResolvePlayerName(name string) (int, []models.PlayerCandidate, error) {
 	normalizedInput := normalizeForComparison(name)
 
 	// Fast path: check cache first (O(1))
 	if id, found := r.playerCache.Get(normalizedInput); found {
-		return id, nil
+		return id, nil, nil
+	}
+
+	// Aliases bound by admins or learned from earlier fuzzy matches win over similarity
+	if id, err := r.findPlayerByAlias(normalizedInput); err != nil {
+		return 0, nil, err
+	} else if id != 0 {
+		r.playerCache.Set(normalizedInput, id)
+		return id, nil, nil
 	}
 
 	// Cache miss: check database for exact or similar matches
 	existingPlayers, err := r.GetAllPlayers()
-	if err == nil && len(existingPlayers) > 0 {
-		for _, p := range existingPlayers {
-			normalizedExisting := normalizeForComparison(p.Name)
-
-			// Exact match
-			if normalizedInput == normalizedExisting {
-				r.playerCache.Set(normalizedInput, p.ID)
-				return p.ID, nil
-			}
+	if err != nil {
+		return 0, nil, err
+	}
 
-			// Fuzzy match (similarity)
-			if similarityScore(normalizedInput, normalizedExisting) > similarityThreshold {
-				r.playerCache.Set(normalizedInput, p.ID)
-				return p.ID, nil
-			}
+	var candidates []models.PlayerCandidate
+	for _, p := range existingPlayers {
+		normalizedExisting := normalizeForComparison(p.Name)
+
+		// Exact match
+		if normalizedInput == normalizedExisting {
+			r.playerCache.Set(normalizedInput, p.ID)
+			return p.ID, nil, nil
+		}
+
+		// Short names are too easy to confuse
+		if !isFuzzyCandidate(normalizedInput, normalizedExisting) {
+			continue
 		}
+		if score := similarityScore(normalizedInput, normalizedExisting); score >= ambiguityThreshold {
+			candidates = append(candidates, models.PlayerCandidate{PlayerID: p.ID, Name: p.Name, Score: score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > maxPlayerCandidates {
+		candidates = candidates[:maxPlayerCandidates]
 	}
 
-	// Player not found - insert new player
+	// Only a single confident match is taken silently, everything else near an existing name needs a human
+	if len(candidates) == 1 && candidates[0].Score > similarityThreshold {
+		id := candidates[0].PlayerID
+		if err := r.saveAlias(name, id, models.AliasSourceFuzzy, ""); err != nil {
+			return 0, nil, err
+		}
+		r.playerCache.Set(normalizedInput, id)
+		return id, nil, nil
+	}
+	return 0, candidates, nil
+}
+
+// CreatePlayer inserts a new player, or returns the existing one with exactly this name
+func (r *MatchPostgres) CreatePlayer(name string) (int, error) {
 	var id int
-	err = r.db.QueryRow(`
+	err := r.db.QueryRow(`
 		INSERT INTO players (name) VALUES ($1)
 		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
 		RETURNING id`, name).Scan(&id)
@@ -319,11 +642,71 @@ func (r *MatchPostgres) EnsurePlayerExists(name string) (int, error) {
 	}
 
 	// Cache the newly created player
-	r.playerCache.Set(normalizedInput, id)
+	r.playerCache.Set(normalizeForComparison(name), id)
 
 	return id, nil
 }
 
+// AssignResultPlayer binds an unresolved result to the player picked by a reviewer. A non-empty alias is
+// remembered as a manual alias of the player in the same transaction
+func (r *MatchPostgres) AssignResultPlayer(matchID, resultID, playerID int, matchSignature, alias, aliasBy string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE player_results SET player_id = $3, candidate_ids = NULL
+		WHERE id = $2 AND match_id = $1 AND is_deleted = FALSE
+	`, matchID, resultID, playerID)
+	if err != nil {
+		return fmt.Errorf("failed to assign player: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec("UPDATE matches SET match_signature = $2 WHERE id = $1", matchID, matchSignature); err != nil {
+		return fmt.Errorf("failed to update match signature: %w", err)
+	}
+	if alias != "" {
+		if err := insertAlias(tx, alias, playerID, models.AliasSourceManual, aliasBy); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if alias != "" {
+		r.playerCache.Set(normalizeForComparison(alias), playerID)
+	}
+	return nil
+}
+
+// resolvePlayerIDs keeps IDs already resolved by the caller and looks up the rest by name,
+// ambiguous results stay unbound
+func (r *MatchPostgres) resolvePlayerIDs(players []models.PlayerResult) ([]int, error) {
+	playerIDs := make([]int, len(players))
+	for i, p := range players {
+		if p.PlayerID != 0 || p.Unresolved() {
+			playerIDs[i] = p.PlayerID
+			continue
+		}
+		playerID, err := r.EnsurePlayerExists(p.PlayerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to ensure player exists: %w", err)
+		}
+		playerIDs[i] = playerID
+	}
+	return playerIDs, nil
+}
+
 // batchInsertPlayerResults inserts all player results in a single query
 func (r *MatchPostgres) batchInsertPlayerResults(
 	tx *sql.Tx,
@@ -337,29 +720,44 @@ func (r *MatchPostgres) batchInsertPlayerResults(
 
 	// Build batch INSERT query with multiple VALUES
 	query := `INSERT INTO player_results 
-              (match_id, player_id, player_name, result, kills, deaths, assists) 
+              (match_id, player_id, player_name, result, kills, deaths, assists, team,
+               champion, gold, hero_damage, damage_taken, turret_damage, teamfight_pct, medal, candidate_ids,
+               raw_name, name_confidence, stats_confidence) 
               VALUES `
 
-	values := make([]interface{}, 0, len(players)*7)
+	values := make([]interface{}, 0, len(players)*playerResultColumns)
 	placeholders := make([]string, 0, len(players))
 
 	for i, p := range players {
-		// Generate placeholders: ($1, $2, ..., $7), ($8, $9, ..., $14), ...
-		offset := i * 7
-		placeholders = append(placeholders,
-			fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-				offset+1, offset+2, offset+3, offset+4,
-				offset+5, offset+6, offset+7))
+		// Generate placeholders: ($1, ..., $19), ($20, ..., $38), ...
+		offset := i * playerResultColumns
+		args := make([]string, playerResultColumns)
+		for j := range args {
+			args[j] = fmt.Sprintf("$%d", offset+j+1)
+		}
+		placeholders = append(placeholders, "("+strings.Join(args, ", ")+")")
 
 		// Add values in correct order
 		values = append(values,
 			matchID,
-			playerIDs[i],
+			nullableID(playerIDs[i]),
 			p.PlayerName,
 			p.Result,
 			p.Kills,
 			p.Deaths,
-			p.Assists)
+			p.Assists,
+			p.Team,
+			p.Champion,
+			p.Gold,
+			p.HeroDamage,
+			p.DamageTaken,
+			p.TurretDamage,
+			p.TeamfightPct,
+			p.Medal,
+			candidateIDs(p.Candidates),
+			p.RawName,
+			p.NameConfidence,
+			p.StatsConfidence)
 	}
 
 	// Complete query: INSERT ... VALUES (...), (...), (...)
@@ -374,36 +772,116 @@ func (r *MatchPostgres) batchInsertPlayerResults(
 	return nil
 }
 
+const matchColumns = `id, game, COALESCE(file_hash, ''), COALESCE(source_hash, ''), region_index, is_manual, match_signature, status, COALESCE(reviewed_by, ''), COALESCE(review_note, ''),
+	COALESCE(perceptual_hash, 0), COALESCE(possible_duplicate_of, 0),
+	COALESCE(blue_score, 0), COALESCE(red_score, 0), COALESCE(duration_sec, 0), COALESCE(game_mode, ''), played_at,
+	COALESCE(platform, ''), COALESCE(submitter_id, ''), COALESCE(submitter_name, ''), COALESCE(guild_id, ''),
+	COALESCE(channel_id, ''), COALESCE(message_id, ''), COALESCE(attachment_url, ''),
+	created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMatch(row rowScanner) (*models.Match, error) {
+	var m models.Match
+	var perceptualHash int64
+	var playedAt sql.NullTime
+	err := row.Scan(&m.ID, &m.Game, &m.FileHash, &m.SourceHash, &m.RegionIndex, &m.Manual, &m.MatchSignature, &m.Status, &m.ReviewedBy, &m.ReviewNote,
+		&perceptualHash, &m.PossibleDuplicateOf,
+		&m.BlueScore, &m.RedScore, &m.DurationSec, &m.GameMode, &playedAt,
+		&m.Source.Platform, &m.Source.SubmitterID, &m.Source.SubmitterName, &m.Source.GuildID,
+		&m.Source.ChannelID, &m.Source.MessageID, &m.Source.AttachmentURL,
+		&m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.PerceptualHash = uint64(perceptualHash)
+	m.PlayedAt = nullTimePtr(playedAt)
+	return &m, nil
+}
+
+func nullablePerceptualHash(hash uint64) interface{} {
+	if hash == 0 {
+		return nil
+	}
+	return int64(hash)
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+func nullableID(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+func candidateIDs(candidates []models.PlayerCandidate) interface{} {
+	if len(candidates) == 0 {
+		return nil
+	}
+	ids := make(pq.Int64Array, len(candidates))
+	for i, c := range candidates {
+		ids[i] = int64(c.PlayerID)
+	}
+	return ids
+}
+
+// normalizeForComparison lowercases the name and strips decorations, keeping letters of any script.
+// Names made only of symbols are kept as is so they never collapse into an empty key.
 func normalizeForComparison(name string) string {
-	name = strings.TrimSpace(name)
-	name = strings.ToLower(name)
+	name = strings.ToLower(strings.TrimSpace(name))
 
 	var result strings.Builder
 	for _, r := range name {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == ' ' {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' {
 			result.WriteRune(r)
 		}
 	}
-	return strings.TrimSpace(result.String())
+
+	normalized := strings.TrimSpace(result.String())
+	if normalized == "" {
+		return name
+	}
+	return normalized
+}
+
+func isFuzzyCandidate(a, b string) bool {
+	return utf8.RuneCountInString(a) >= minFuzzyNameLength && utf8.RuneCountInString(b) >= minFuzzyNameLength
 }
 
 func similarityScore(a, b string) float64 {
 	if a == b {
 		return 1.0
 	}
-	if len(a) == 0 || len(b) == 0 {
+
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
 		return 0.0
 	}
 
-	distance := levenshteinDistance(a, b)
-	maxLen := len(a)
-	if len(b) > maxLen {
-		maxLen = len(b)
+	distance := levenshteinDistance(ra, rb)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
 	}
 	return 1.0 - float64(distance)/float64(maxLen)
 }
 
-func levenshteinDistance(a, b string) int {
+func levenshteinDistance(a, b []rune) int {
 	if len(a) == 0 {
 		return len(b)
 	}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+	"valhalla/internal/models"
+)
+
+// GetSubmitterStats counts uploads per submitter, deleted matches included so abuse stays visible
+func (r *MatchPostgres) GetSubmitterStats() ([]models.SubmitterStats, error) {
+	rows, err := r.db.Query(`
+		SELECT COALESCE(platform, ''), submitter_id, MAX(COALESCE(submitter_name, '')),
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE status = $1 AND is_deleted = FALSE),
+		       COUNT(*) FILTER (WHERE status = $2 AND is_deleted = FALSE),
+		       COUNT(*) FILTER (WHERE status = $3 AND is_deleted = FALSE),
+		       COUNT(*) FILTER (WHERE is_deleted = TRUE),
+		       MAX(created_at)
+		FROM matches
+		WHERE submitter_id IS NOT NULL
+		GROUP BY platform, submitter_id
+		ORDER BY COUNT(*) DESC
+	`, models.MatchStatusApproved, models.MatchStatusPending, models.MatchStatusRejected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submitter stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.SubmitterStats
+	for rows.Next() {
+		var st models.SubmitterStats
+		if err := rows.Scan(&st.Platform, &st.SubmitterID, &st.SubmitterName, &st.Total,
+			&st.Approved, &st.Pending, &st.Rejected, &st.Deleted, &st.LastUpload); err != nil {
+			continue
+		}
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+// GetBySubmitter returns the latest live matches uploaded by a user, without player results
+func (r *MatchPostgres) GetBySubmitter(platform, submitterID string, limit int) ([]models.Match, error) {
+	rows, err := r.db.Query(`SELECT `+matchColumns+` FROM matches
+		WHERE platform = $1 AND submitter_id = $2 AND is_deleted = FALSE
+		ORDER BY created_at DESC
+		LIMIT $3`, platform, submitterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get matches by submitter: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []models.Match
+	for rows.Next() {
+		m, err := scanMatch(rows)
+		if err != nil {
+			continue
+		}
+		matches = append(matches, *m)
+	}
+	return matches, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"valhalla/internal/models"
+)
+
+// AddAlias binds an OCR spelling to a player, replacing any previous binding of the same spelling
+func (r *MatchPostgres) AddAlias(alias string, playerID int, createdBy string) error {
+	if _, err := r.GetPlayerNameByID(playerID); err != nil {
+		return err
+	}
+	return r.saveAlias(alias, playerID, models.AliasSourceManual, createdBy)
+}
+
+func (r *MatchPostgres) RemoveAlias(alias string) error {
+	normalized := normalizeForComparison(alias)
+
+	res, err := r.db.Exec("DELETE FROM player_aliases WHERE normalized_alias = $1", normalized)
+	if err != nil {
+		return fmt.Errorf("failed to remove alias: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	r.playerCache.Delete(normalized)
+	return nil
+}
+
+// GetAliases returns aliases of a player, or of all players when playerID is 0
+func (r *MatchPostgres) GetAliases(playerID int) ([]models.PlayerAlias, error) {
+	rows, err := r.db.Query(`
+		SELECT a.id, a.alias, a.player_id, p.name, a.source, COALESCE(a.created_by, ''), a.created_at
+		FROM player_aliases a
+		JOIN players p ON p.id = a.player_id
+		WHERE ($1 = 0 OR a.player_id = $1) AND p.is_deleted = FALSE
+		ORDER BY a.player_id, a.alias
+	`, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []models.PlayerAlias
+	for rows.Next() {
+		var a models.PlayerAlias
+		if err := rows.Scan(&a.ID, &a.Alias, &a.PlayerID, &a.PlayerName, &a.Source, &a.CreatedBy, &a.CreatedAt); err != nil {
+			continue
+		}
+		aliases = append(aliases, a)
+	}
+	return aliases, nil
+}
+
+func (r *MatchPostgres) saveAlias(alias string, playerID int, source, createdBy string) error {
+	if err := insertAlias(r.db, alias, playerID, source, createdBy); err != nil {
+		return err
+	}
+	if source == models.AliasSourceManual {
+		r.playerCache.Set(normalizeForComparison(alias), playerID)
+	}
+	return nil
+}
+
+// sqlExecer and sqlQueryer are implemented by both *sql.DB and *sql.Tx
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+type sqlQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func insertAlias(db sqlExecer, alias string, playerID int, source, createdBy string) error {
+	normalized := normalizeForComparison(alias)
+
+	// Manual bindings overwrite anything, learned ones never override an existing binding
+	query := `
+		INSERT INTO player_aliases (alias, normalized_alias, player_id, source, created_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (normalized_alias) DO NOTHING`
+	if source == models.AliasSourceManual {
+		query = `
+		INSERT INTO player_aliases (alias, normalized_alias, player_id, source, created_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (normalized_alias) DO UPDATE SET
+			alias = EXCLUDED.alias,
+			player_id = EXCLUDED.player_id,
+			source = EXCLUDED.source,
+			created_by = EXCLUDED.created_by,
+			created_at = NOW()`
+	}
+
+	if _, err := db.Exec(query, alias, normalized, playerID, source, createdBy); err != nil {
+		return fmt.Errorf("failed to save alias: %w", err)
+	}
+	return nil
+}
+
+func (r *MatchPostgres) findPlayerByAlias(normalized string) (int, error) {
+	var playerID int
+	err := r.db.QueryRow(`
+		SELECT a.player_id FROM player_aliases a
+		JOIN players p ON p.id = a.player_id
+		WHERE a.normalized_alias = $1 AND p.is_deleted = FALSE
+	`, normalized).Scan(&playerID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find alias: %w", err)
+	}
+	return playerID, nil
+}
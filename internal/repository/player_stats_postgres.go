@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"valhalla/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// countedResults selects the results that make up the current standings of a game: approved live matches
+// played since the season start, without the ones before a player's personal reset.
+// $1 is the game, $2 the season start and $3 the player IDs to limit to, NULL for everyone
+const countedResults = `
+	FROM player_results pr
+	JOIN matches m ON m.id = pr.match_id
+	LEFT JOIN player_resets rs ON rs.player_name = pr.player_name
+	WHERE m.game = $1
+	  AND m.status = '` + models.MatchStatusApproved + `'
+	  AND m.is_deleted = FALSE AND pr.is_deleted = FALSE
+	  AND pr.player_id IS NOT NULL
+	  AND COALESCE(m.played_at, m.created_at) >= $2
+	  AND (rs.reset_date IS NULL OR COALESCE(m.played_at, m.created_at) >= rs.reset_date)
+	  AND ($3::int[] IS NULL OR pr.player_id = ANY($3))
+`
+
+// RefreshPlayerStats recomputes the stored standings of the given players of a game, of everyone when playerIDs is nil
+func (r *MatchPostgres) RefreshPlayerStats(game string, since time.Time, playerIDs []int) error {
+	var ids pq.Int64Array
+	if playerIDs != nil {
+		ids = make(pq.Int64Array, 0, len(playerIDs))
+		for _, id := range playerIDs {
+			ids = append(ids, int64(id))
+		}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"player_stats", "player_hero_stats"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE game = $1 AND ($2::int[] IS NULL OR player_id = ANY($2))`, game, ids); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO player_stats (game, player_id, matches, wins, losses, kills, deaths, assists,
+		                          gold, hero_damage, damage_taken, turret_damage, teamfight_pct, mvps)
+		SELECT $1, pr.player_id, COUNT(*),
+		       COUNT(*) FILTER (WHERE UPPER(pr.result) = 'WIN'),
+		       COUNT(*) FILTER (WHERE UPPER(pr.result) <> 'WIN'),
+		       SUM(pr.kills), SUM(pr.deaths), SUM(pr.assists),
+		       SUM(COALESCE(pr.gold, 0)), SUM(COALESCE(pr.hero_damage, 0)), SUM(COALESCE(pr.damage_taken, 0)),
+		       SUM(COALESCE(pr.turret_damage, 0)), SUM(COALESCE(pr.teamfight_pct, 0)),
+		       COUNT(*) FILTER (WHERE pr.medal = '`+models.MedalMVP+`')
+		`+countedResults+`
+		GROUP BY pr.player_id
+	`, game, since, ids)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate player stats: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO player_hero_stats (game, player_id, champion, matches)
+		SELECT $1, pr.player_id, pr.champion, COUNT(*)
+		`+countedResults+`
+		  AND COALESCE(pr.champion, '') <> ''
+		GROUP BY pr.player_id, pr.champion
+	`, game, since, ids)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate player heroes: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetPlayerAggregates reads the stored standings of a game, of a single player when playerID is not 0
+func (r *MatchPostgres) GetPlayerAggregates(game string, playerID int) ([]models.PlayerAggregate, error) {
+	rows, err := r.db.Query(`
+		SELECT ps.game, ps.player_id, p.name, ps.matches, ps.wins, ps.losses, ps.kills, ps.deaths, ps.assists,
+		       ps.gold, ps.hero_damage, ps.damage_taken, ps.turret_damage, ps.teamfight_pct, ps.mvps
+		FROM player_stats ps
+		JOIN players p ON p.id = ps.player_id
+		WHERE ps.game = $1 AND ($2 = 0 OR ps.player_id = $2) AND p.is_deleted = FALSE
+	`, game, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player stats: %w", err)
+	}
+	defer rows.Close()
+
+	var aggregates []models.PlayerAggregate
+	index := make(map[int]int)
+	for rows.Next() {
+		a := models.PlayerAggregate{Heroes: make(map[string]int)}
+		if err := rows.Scan(&a.Game, &a.PlayerID, &a.PlayerName, &a.Matches, &a.Wins, &a.Losses, &a.Kills, &a.Deaths,
+			&a.Assists, &a.Gold, &a.HeroDamage, &a.DamageTaken, &a.TurretDamage, &a.TeamfightPct, &a.MVPs); err != nil {
+			continue
+		}
+		index[a.PlayerID] = len(aggregates)
+		aggregates = append(aggregates, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read player stats: %w", err)
+	}
+
+	heroRows, err := r.db.Query(`
+		SELECT player_id, champion, matches
+		FROM player_hero_stats
+		WHERE game = $1 AND ($2 = 0 OR player_id = $2)
+	`, game, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player heroes: %w", err)
+	}
+	defer heroRows.Close()
+
+	for heroRows.Next() {
+		var id, matches int
+		var champion string
+		if err := heroRows.Scan(&id, &champion, &matches); err != nil {
+			continue
+		}
+		if i, ok := index[id]; ok {
+			aggregates[i].Heroes[champion] = matches
+		}
+	}
+	return aggregates, nil
+}
+
+// AggregatePlayerStats sums the counted results matching the filter on the fly, for views the stored
+// standings cannot answer. Personal resets apply as in the stored standings
+func (r *MatchPostgres) AggregatePlayerStats(filter models.StatsFilter) ([]models.PlayerAggregate, error) {
+	conditions := []string{
+		"m.game = $1",
+		"m.status = '" + models.MatchStatusApproved + "'",
+		"m.is_deleted = FALSE AND pr.is_deleted = FALSE",
+		"pr.player_id IS NOT NULL",
+		"(rs.reset_date IS NULL OR COALESCE(m.played_at, m.created_at) >= rs.reset_date)",
+	}
+	args := []interface{}{filter.Game}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if !filter.From.IsZero() {
+		addCondition("COALESCE(m.played_at, m.created_at) >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("COALESCE(m.played_at, m.created_at) < $%d", filter.To)
+	}
+	if filter.Hero != "" {
+		addCondition("LOWER(pr.champion) = LOWER($%d)", filter.Hero)
+	}
+	if filter.Role != "" {
+		addCondition("EXISTS (SELECT 1 FROM profile_links pl WHERE pl.discord_player_id = pr.player_id AND LOWER(pl.main_role) = LOWER($%d))", filter.Role)
+	}
+	from := `
+		FROM player_results pr
+		JOIN matches m ON m.id = pr.match_id
+		JOIN players p ON p.id = pr.player_id AND p.is_deleted = FALSE
+		LEFT JOIN player_resets rs ON rs.player_name = pr.player_name
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	rows, err := r.db.Query(`
+		SELECT pr.player_id, MIN(p.name), COUNT(*),
+		       COUNT(*) FILTER (WHERE UPPER(pr.result) = 'WIN'),
+		       COUNT(*) FILTER (WHERE UPPER(pr.result) <> 'WIN'),
+		       SUM(pr.kills), SUM(pr.deaths), SUM(pr.assists),
+		       SUM(COALESCE(pr.gold, 0)), SUM(COALESCE(pr.hero_damage, 0)), SUM(COALESCE(pr.damage_taken, 0)),
+		       SUM(COALESCE(pr.turret_damage, 0)), SUM(COALESCE(pr.teamfight_pct, 0)),
+		       COUNT(*) FILTER (WHERE pr.medal = '`+models.MedalMVP+`')
+		`+from+`
+		GROUP BY pr.player_id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate player stats: %w", err)
+	}
+	defer rows.Close()
+
+	var aggregates []models.PlayerAggregate
+	index := make(map[int]int)
+	for rows.Next() {
+		a := models.PlayerAggregate{Game: filter.Game, Heroes: make(map[string]int)}
+		if err := rows.Scan(&a.PlayerID, &a.PlayerName, &a.Matches, &a.Wins, &a.Losses, &a.Kills, &a.Deaths, &a.Assists,
+			&a.Gold, &a.HeroDamage, &a.DamageTaken, &a.TurretDamage, &a.TeamfightPct, &a.MVPs); err != nil {
+			continue
+		}
+		index[a.PlayerID] = len(aggregates)
+		aggregates = append(aggregates, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read player stats: %w", err)
+	}
+
+	heroRows, err := r.db.Query(`
+		SELECT pr.player_id, pr.champion, COUNT(*)
+		`+from+` AND COALESCE(pr.champion, '') <> ''
+		GROUP BY pr.player_id, pr.champion
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate player heroes: %w", err)
+	}
+	defer heroRows.Close()
+
+	for heroRows.Next() {
+		var id, matches int
+		var champion string
+		if err := heroRows.Scan(&id, &champion, &matches); err != nil {
+			continue
+		}
+		if i, ok := index[id]; ok {
+			aggregates[i].Heroes[champion] = matches
+		}
+	}
+	return aggregates, nil
+}
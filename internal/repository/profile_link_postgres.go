@@ -206,12 +206,14 @@ func (r *ProfileLinkPostgres) GetDiscordStatsByPlayerID(playerID int) (wins, los
 
 	err = r.db.QueryRow(`
 		SELECT 
-			COALESCE(SUM(CASE WHEN result = 'WIN' THEN 1 ELSE 0 END), 0) as wins,
-			COALESCE(SUM(CASE WHEN result = 'LOSE' THEN 1 ELSE 0 END), 0) as losses,
-			COALESCE(SUM(kills), 0) as kills,
-			COALESCE(SUM(deaths), 0) as deaths,
-			COALESCE(SUM(assists), 0) as assists
-		FROM player_results WHERE player_name = $1 AND is_deleted = FALSE
+			COALESCE(SUM(CASE WHEN pr.result = 'WIN' THEN 1 ELSE 0 END), 0) as wins,
+			COALESCE(SUM(CASE WHEN pr.result = 'LOSE' THEN 1 ELSE 0 END), 0) as losses,
+			COALESCE(SUM(pr.kills), 0) as kills,
+			COALESCE(SUM(pr.deaths), 0) as deaths,
+			COALESCE(SUM(pr.assists), 0) as assists
+		FROM player_results pr
+		JOIN matches m ON m.id = pr.match_id
+		WHERE pr.player_name = $1 AND pr.is_deleted = FALSE AND m.is_deleted = FALSE AND m.status = 'approved'
 	`, playerName).Scan(&wins, &losses, &kills, &deaths, &assists)
 
 	if err != nil {
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+	"valhalla/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ReplaceRatingHistory swaps the rating history of a game played since the date for a fresh replay,
+// changes must be in play order and continue the history kept before the date
+func (r *MatchPostgres) ReplaceRatingHistory(game string, since time.Time, changes []models.RatingChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM rating_history WHERE game = $1 AND played_at >= $2", game, since); err != nil {
+		return fmt.Errorf("failed to clear rating history: %w", err)
+	}
+	var lastSeq int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(seq), 0) FROM rating_history WHERE game = $1", game).Scan(&lastSeq); err != nil {
+		return fmt.Errorf("failed to get rating history position: %w", err)
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("rating_history",
+		"game", "seq", "match_id", "player_id", "rating_before", "rating_after", "played_at"))
+	if err != nil {
+		return fmt.Errorf("failed to prepare rating history copy: %w", err)
+	}
+	for i, c := range changes {
+		if _, err := stmt.Exec(game, lastSeq+i+1, c.MatchID, c.PlayerID, c.Before, c.After, c.PlayedAt); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy rating change: %w", err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to flush rating history: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to close rating history copy: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetRatings returns the latest rating of every rated player of a game keyed by player ID
+func (r *MatchPostgres) GetRatings(game string) (map[int]models.PlayerRating, error) {
+	return r.queryRatings("game = $1", game)
+}
+
+// GetRatingsBefore returns the ratings of a game as they were before the matches played since the date
+func (r *MatchPostgres) GetRatingsBefore(game string, since time.Time) (map[int]models.PlayerRating, error) {
+	return r.queryRatings("game = $1 AND played_at < $2", game, since)
+}
+
+func (r *MatchPostgres) queryRatings(condition string, args ...interface{}) (map[int]models.PlayerRating, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT ON (player_id) player_id, rating_after, rating_after - rating_before,
+		       COUNT(*) OVER (PARTITION BY player_id)
+		FROM rating_history
+		WHERE `+condition+`
+		ORDER BY player_id, seq DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := make(map[int]models.PlayerRating)
+	for rows.Next() {
+		var pr models.PlayerRating
+		if err := rows.Scan(&pr.PlayerID, &pr.Rating, &pr.LastDelta, &pr.Matches); err != nil {
+			continue
+		}
+		ratings[pr.PlayerID] = pr
+	}
+	return ratings, nil
+}
+
+// GetRatingHistory returns the latest rating changes of a player in a game, newest first
+func (r *MatchPostgres) GetRatingHistory(game string, playerID, limit int) ([]models.RatingChange, error) {
+	rows, err := r.db.Query(`
+		SELECT game, match_id, player_id, rating_before, rating_after, played_at
+		FROM rating_history
+		WHERE game = $1 AND player_id = $2
+		ORDER BY seq DESC
+		LIMIT $3
+	`, game, playerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.RatingChange
+	for rows.Next() {
+		var c models.RatingChange
+		if err := rows.Scan(&c.Game, &c.MatchID, &c.PlayerID, &c.Before, &c.After, &c.PlayedAt); err != nil {
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
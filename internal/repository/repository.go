@@ -9,24 +9,65 @@ import (
 type Match interface {
 	Create(match models.Match) (int, error)
 	Exists(fileHash, matchSignature string) (bool, error)
+	FindByFileHash(fileHash string) (int, error)
+	FindBySignature(matchSignature string) (int, error)
 	GetAllAfter(date time.Time) ([]models.Match, error)
+	GetPlayerMatches(game string, playerID int, from, to time.Time) ([]models.Match, error)
+	GetRecentMatches(since time.Time) ([]models.Match, error)
 	Delete(id int) error
 	Restore(id int) error
 	WipeAll() error
 
+	GetByID(id int) (*models.Match, error)
+	GetDeletedByID(id int) (*models.Match, error)
+	GetByStatus(status string) ([]models.Match, error)
+	FindSimilarMatch(perceptualHash uint64, maxDistance int) (int, int, error)
+	SetStatus(id int, status, reviewedBy string) error
+	ReplaceResults(matchID int, matchSignature string, players []models.PlayerResult) error
+	AddMatchEdit(edit models.MatchEdit) error
+	GetMatchEdits(matchID int) ([]models.MatchEdit, error)
+	ReplaceRatingHistory(game string, since time.Time, changes []models.RatingChange) error
+	GetRatings(game string) (map[int]models.PlayerRating, error)
+	GetRatingsBefore(game string, since time.Time) (map[int]models.PlayerRating, error)
+	GetRatingHistory(game string, playerID, limit int) ([]models.RatingChange, error)
+	RefreshPlayerStats(game string, since time.Time, playerIDs []int) error
+	GetPlayerAggregates(game string, playerID int) ([]models.PlayerAggregate, error)
+	AggregatePlayerStats(filter models.StatsFilter) ([]models.PlayerAggregate, error)
+	ReplaceParse(id int, match models.Match) error
+	GetSubmitterStats() ([]models.SubmitterStats, error)
+	GetBySubmitter(platform, submitterID string, limit int) ([]models.Match, error)
+
 	SetSeasonStartDate(date time.Time) error
 	GetSeasonStartDate() (time.Time, error)
+	GetActiveSeason() (*models.Season, error)
+	GetSeasonByName(name string) (*models.Season, error)
+	GetSeasons() ([]models.Season, error)
+	CreateSeason(season models.Season) (int, error)
+	CloseSeason(id int, endedAt time.Time, endedBy string, standings []models.SeasonStanding) error
+	StartNextSeason(closeID int, endedAt time.Time, endedBy string, standings []models.SeasonStanding, next models.Season) (int, error)
+	GetSeasonStandings(seasonID int, game string) ([]models.SeasonStanding, error)
 
 	SetPlayerResetDate(playerName string, date time.Time) error
 	GetPlayerResetDates() (map[string]time.Time, error)
 
 	GetHistory(playerID int, limit int) ([]models.Match, error)
 	EnsurePlayerExists(name string) (int, error)
+	ResolvePlayerName(name string) (int, []models.PlayerCandidate, error)
+	CreatePlayer(name string) (int, error)
+	AssignResultPlayer(matchID, resultID, playerID int, matchSignature, alias, aliasBy string) error
 	GetAllPlayers() ([]models.Player, error)
 	GetPlayerNameByID(id int) (string, error)
 	WipePlayerByID(id int) error
 	RestorePlayer(id int) error
 	RenamePlayer(id int, newName string) error
+
+	AddAlias(alias string, playerID int, createdBy string) error
+	RemoveAlias(alias string) error
+	GetAliases(playerID int) ([]models.PlayerAlias, error)
+
+	GetGameSelection(guildID, channelID string) (*models.GameSelection, error)
+	SetGameSelection(sel models.GameSelection) error
+	DeleteGameSelection(scope, scopeID string) error
 }
 
 type ProfileLink interface {
@@ -68,10 +109,26 @@ type Telegram interface {
 	SetSetting(key, value string) error
 }
 
+type Ingestion interface {
+	CreateJobs(jobs []models.IngestionJob) error
+	ClaimJob(lease time.Duration) (*models.IngestionJob, error)
+	RetryJob(job models.IngestionJob) error
+	FinishJob(job models.IngestionJob) error
+	GetJobsByReply(channelID, messageID string) ([]models.IngestionJob, error)
+}
+
+type AIUsage interface {
+	RecordAIUsage(usage models.AIUsage) error
+	GetAIUsageTotals(filter models.AIUsageFilter) (*models.AIUsageTotals, error)
+	GetAIUsageByUser(filter models.AIUsageFilter, limit int) ([]models.AIUserUsage, error)
+}
+
 type Repository struct {
 	Match
 	ProfileLink
 	Telegram
+	Ingestion
+	AIUsage
 	db *sql.DB
 }
 
@@ -80,6 +137,8 @@ func NewRepository(cfg *Config, db *sql.DB) *Repository {
 		Match:       NewMatchPostgres(db),
 		ProfileLink: NewProfileLinkPostgres(db),
 		Telegram:    NewTelegramPostgres(db),
+		Ingestion:   NewIngestionPostgres(db),
+		AIUsage:     NewAIUsagePostgres(db),
 		db:          db,
 	}
 }
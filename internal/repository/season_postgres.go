@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"valhalla/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const seasonColumns = `id, name, status, started_at, ended_at, COALESCE(started_by, ''), COALESCE(ended_by, '')`
+
+func scanSeason(row rowScanner) (*models.Season, error) {
+	var season models.Season
+	var endedAt sql.NullTime
+	if err := row.Scan(&season.ID, &season.Name, &season.Status, &season.StartedAt, &endedAt,
+		&season.StartedBy, &season.EndedBy); err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		season.EndedAt = &endedAt.Time
+	}
+	return &season, nil
+}
+
+// GetSeasonStartDate returns where the current standings begin: the start of the running season,
+// or the end of the last closed one while no season is running
+func (r *MatchPostgres) GetSeasonStartDate() (time.Time, error) {
+	var start time.Time
+	err := r.db.QueryRow(`
+		SELECT CASE WHEN status = $1 THEN started_at ELSE ended_at END
+		FROM seasons
+		ORDER BY status = $1 DESC, ended_at DESC NULLS LAST
+		LIMIT 1
+	`, models.SeasonActive).Scan(&start)
+	if err == sql.ErrNoRows {
+		return time.Date(defaultSeasonStartYear, defaultSeasonStartMonth, defaultSeasonStartDay, 0, 0, 0, 0, time.UTC), nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get season start date: %w", err)
+	}
+	return start, nil
+}
+
+// SetSeasonStartDate moves the start of the running season
+func (r *MatchPostgres) SetSeasonStartDate(date time.Time) error {
+	res, err := r.db.Exec("UPDATE seasons SET started_at = $1 WHERE status = $2", date, models.SeasonActive)
+	if err != nil {
+		return fmt.Errorf("failed to set season start date: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetActiveSeason returns the running season, nil when there is none
+func (r *MatchPostgres) GetActiveSeason() (*models.Season, error) {
+	season, err := scanSeason(r.db.QueryRow(`SELECT `+seasonColumns+` FROM seasons WHERE status = $1`, models.SeasonActive))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active season: %w", err)
+	}
+	return season, nil
+}
+
+// GetSeasonByName finds a season by its case-insensitive name, nil when there is none
+func (r *MatchPostgres) GetSeasonByName(name string) (*models.Season, error) {
+	season, err := scanSeason(r.db.QueryRow(`SELECT `+seasonColumns+` FROM seasons WHERE LOWER(name) = LOWER($1)`, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get season: %w", err)
+	}
+	return season, nil
+}
+
+// GetSeasons returns all seasons, newest first
+func (r *MatchPostgres) GetSeasons() ([]models.Season, error) {
+	rows, err := r.db.Query(`SELECT ` + seasonColumns + ` FROM seasons ORDER BY started_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seasons: %w", err)
+	}
+	defer rows.Close()
+
+	var seasons []models.Season
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			continue
+		}
+		seasons = append(seasons, *season)
+	}
+	return seasons, nil
+}
+
+func (r *MatchPostgres) CreateSeason(season models.Season) (int, error) {
+	return createSeason(r.db, season)
+}
+
+// StartNextSeason closes the running season with its final standings and starts the next one in one transaction,
+// so a failed start does not leave the server without a season
+func (r *MatchPostgres) StartNextSeason(closeID int, endedAt time.Time, endedBy string, standings []models.SeasonStanding, next models.Season) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := closeSeason(tx, closeID, endedAt, endedBy, standings); err != nil {
+		return 0, err
+	}
+	id, err := createSeason(tx, next)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+func createSeason(db sqlQueryer, season models.Season) (int, error) {
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO seasons (name, status, started_at, started_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id
+	`, season.Name, models.SeasonActive, season.StartedAt, season.StartedBy).Scan(&id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return 0, fmt.Errorf("failed to create season: a season with this name already exists or another one is running")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to create season: %w", err)
+	}
+	return id, nil
+}
+
+// CloseSeason ends a running season and stores its final standings in one transaction
+func (r *MatchPostgres) CloseSeason(id int, endedAt time.Time, endedBy string, standings []models.SeasonStanding) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := closeSeason(tx, id, endedAt, endedBy, standings); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func closeSeason(tx *sql.Tx, id int, endedAt time.Time, endedBy string, standings []models.SeasonStanding) error {
+	res, err := tx.Exec(`
+		UPDATE seasons SET status = $2, ended_at = $3, ended_by = NULLIF($4, '')
+		WHERE id = $1 AND status = $5
+	`, id, models.SeasonClosed, endedAt, endedBy, models.SeasonActive)
+	if err != nil {
+		return fmt.Errorf("failed to close season: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("season_standings",
+		"season_id", "game", "player_id", "player_name", "rank", "matches", "wins", "losses", "kills", "deaths", "assists",
+		"kda", "rating", "gold", "hero_damage", "damage_taken", "turret_damage", "teamfight_pct", "mvps", "heroes"))
+	if err != nil {
+		return fmt.Errorf("failed to prepare standings copy: %w", err)
+	}
+	for _, st := range standings {
+		heroes, err := json.Marshal(st.Heroes)
+		if err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to encode heroes: %w", err)
+		}
+		if _, err := stmt.Exec(id, st.Game, st.PlayerID, st.PlayerName, st.Rank, st.Matches, st.Wins, st.Losses,
+			st.Kills, st.Deaths, st.Assists, st.KDA, st.Rating, st.Gold, st.HeroDamage, st.DamageTaken,
+			st.TurretDamage, st.TeamfightPct, st.MVPs, string(heroes)); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy standing: %w", err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to flush standings: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to close standings copy: %w", err)
+	}
+	return nil
+}
+
+// GetSeasonStandings returns the frozen standings of a closed season in one game, in final order
+func (r *MatchPostgres) GetSeasonStandings(seasonID int, game string) ([]models.SeasonStanding, error) {
+	rows, err := r.db.Query(`
+		SELECT season_id, game, player_id, player_name, rank, matches, wins, losses, kills, deaths, assists,
+		       kda, rating, gold, hero_damage, damage_taken, turret_damage, teamfight_pct, mvps, COALESCE(heroes, '{}')
+		FROM season_standings
+		WHERE season_id = $1 AND game = $2
+		ORDER BY rank
+	`, seasonID, game)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get season standings: %w", err)
+	}
+	defer rows.Close()
+
+	var standings []models.SeasonStanding
+	for rows.Next() {
+		var st models.SeasonStanding
+		var heroes []byte
+		if err := rows.Scan(&st.SeasonID, &st.Game, &st.PlayerID, &st.PlayerName, &st.Rank, &st.Matches, &st.Wins,
+			&st.Losses, &st.Kills, &st.Deaths, &st.Assists, &st.KDA, &st.Rating, &st.Gold, &st.HeroDamage,
+			&st.DamageTaken, &st.TurretDamage, &st.TeamfightPct, &st.MVPs, &heroes); err != nil {
+			continue
+		}
+		if err := json.Unmarshal(heroes, &st.Heroes); err != nil {
+			st.Heroes = make(map[string]int)
+		}
+		standings = append(standings, st)
+	}
+	return standings, nil
+}
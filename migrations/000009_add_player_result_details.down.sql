@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_player_results_champion;
+
+ALTER TABLE player_results DROP COLUMN IF EXISTS gold;
+ALTER TABLE player_results DROP COLUMN IF EXISTS hero_damage;
+ALTER TABLE player_results DROP COLUMN IF EXISTS damage_taken;
+ALTER TABLE player_results DROP COLUMN IF EXISTS turret_damage;
+ALTER TABLE player_results DROP COLUMN IF EXISTS teamfight_pct;
+ALTER TABLE player_results DROP COLUMN IF EXISTS medal;
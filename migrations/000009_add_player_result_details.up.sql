@@ -0,0 +1,8 @@
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS gold INT DEFAULT 0;
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS hero_damage INT DEFAULT 0;
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS damage_taken INT DEFAULT 0;
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS turret_damage INT DEFAULT 0;
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS teamfight_pct NUMERIC(5, 2) DEFAULT 0;
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS medal VARCHAR(16) DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_player_results_champion ON player_results(champion);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_matches_status;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE matches DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE matches DROP COLUMN IF EXISTS status;
@@ -0,0 +1,8 @@
+-- Existing matches were counted immediately, so they are treated as approved
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'approved';
+ALTER TABLE matches ALTER COLUMN status SET DEFAULT 'pending';
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(64);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_matches_status ON matches(status);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_matches_perceptual_hash;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS possible_duplicate_of;
+ALTER TABLE matches DROP COLUMN IF EXISTS perceptual_hash;
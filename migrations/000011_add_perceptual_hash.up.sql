@@ -0,0 +1,4 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS perceptual_hash BIGINT;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS possible_duplicate_of INT REFERENCES matches(id);
+
+CREATE INDEX IF NOT EXISTS idx_matches_perceptual_hash ON matches(perceptual_hash) WHERE perceptual_hash IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_matches_match_date;
+
+ALTER TABLE player_results DROP COLUMN IF EXISTS team;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS played_at;
+ALTER TABLE matches DROP COLUMN IF EXISTS game_mode;
+ALTER TABLE matches DROP COLUMN IF EXISTS duration_sec;
+ALTER TABLE matches DROP COLUMN IF EXISTS red_score;
+ALTER TABLE matches DROP COLUMN IF EXISTS blue_score;
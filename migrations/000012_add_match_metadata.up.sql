@@ -0,0 +1,10 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS blue_score INT DEFAULT 0;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS red_score INT DEFAULT 0;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS duration_sec INT DEFAULT 0;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS game_mode VARCHAR(64) DEFAULT '';
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS played_at TIMESTAMPTZ;
+
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS team VARCHAR(8) DEFAULT '';
+
+-- Season filtering uses the in-game time when it was read from the scoreboard
+CREATE INDEX IF NOT EXISTS idx_matches_match_date ON matches(COALESCE(played_at, created_at));
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS player_aliases (
+    id SERIAL PRIMARY KEY,
+    alias VARCHAR(255) NOT NULL,
+    normalized_alias VARCHAR(255) UNIQUE NOT NULL,
+    player_id INT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    source VARCHAR(16) NOT NULL DEFAULT 'manual',
+    created_by VARCHAR(64),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_aliases_player_id ON player_aliases(player_id);
@@ -0,0 +1,3 @@
+DELETE FROM player_results WHERE player_id IS NULL;
+ALTER TABLE player_results DROP COLUMN IF EXISTS candidate_ids;
+ALTER TABLE player_results ALTER COLUMN player_id SET NOT NULL;
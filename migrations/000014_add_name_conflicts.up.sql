@@ -0,0 +1,3 @@
+-- Results whose name matched several players stay unbound until a reviewer picks one
+ALTER TABLE player_results ALTER COLUMN player_id DROP NOT NULL;
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS candidate_ids INT[];
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_matches_submitter;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS attachment_url;
+ALTER TABLE matches DROP COLUMN IF EXISTS message_id;
+ALTER TABLE matches DROP COLUMN IF EXISTS channel_id;
+ALTER TABLE matches DROP COLUMN IF EXISTS guild_id;
+ALTER TABLE matches DROP COLUMN IF EXISTS submitter_name;
+ALTER TABLE matches DROP COLUMN IF EXISTS submitter_id;
+ALTER TABLE matches DROP COLUMN IF EXISTS platform;
@@ -0,0 +1,9 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS platform VARCHAR(16);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS submitter_id VARCHAR(64);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS submitter_name VARCHAR(255);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS guild_id VARCHAR(64);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS channel_id VARCHAR(64);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS message_id VARCHAR(64);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS attachment_url TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_matches_submitter ON matches(platform, submitter_id);
@@ -0,0 +1,5 @@
+ALTER TABLE matches DROP COLUMN IF EXISTS review_note;
+
+ALTER TABLE player_results DROP COLUMN IF EXISTS stats_confidence;
+ALTER TABLE player_results DROP COLUMN IF EXISTS name_confidence;
+ALTER TABLE player_results DROP COLUMN IF EXISTS raw_name;
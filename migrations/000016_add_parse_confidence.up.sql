@@ -0,0 +1,5 @@
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS raw_name VARCHAR(255);
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS name_confidence NUMERIC(3,2);
+ALTER TABLE player_results ADD COLUMN IF NOT EXISTS stats_confidence NUMERIC(3,2);
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS review_note TEXT;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_matches_source_hash;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS region_index;
+ALTER TABLE matches DROP COLUMN IF EXISTS source_hash;
@@ -0,0 +1,4 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS source_hash VARCHAR(64);
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS region_index INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_matches_source_hash ON matches(source_hash);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS game_selections;
+
+DROP INDEX IF EXISTS idx_matches_game;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS game;
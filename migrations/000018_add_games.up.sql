@@ -0,0 +1,12 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS game VARCHAR(32) NOT NULL DEFAULT 'mlbb';
+
+CREATE INDEX IF NOT EXISTS idx_matches_game ON matches(game);
+
+CREATE TABLE IF NOT EXISTS game_selections (
+    scope VARCHAR(16) NOT NULL,
+    scope_id VARCHAR(64) NOT NULL,
+    game VARCHAR(32) NOT NULL,
+    selected_by VARCHAR(64),
+    selected_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (scope, scope_id)
+);
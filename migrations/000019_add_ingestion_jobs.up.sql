@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS ingestion_jobs (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    platform VARCHAR(16) NOT NULL,
+    submitter_id VARCHAR(64),
+    submitter_name VARCHAR(255),
+    guild_id VARCHAR(64),
+    channel_id VARCHAR(64),
+    message_id VARCHAR(64),
+    attachment_url TEXT NOT NULL,
+    attachment_index INT NOT NULL DEFAULT 1,
+    reply_channel_id VARCHAR(64),
+    reply_message_id VARCHAR(64),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at TIMESTAMPTZ,
+    last_error TEXT,
+    match_ids INT[],
+    duplicates INT NOT NULL DEFAULT 0,
+    errors TEXT[],
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingestion_jobs_due ON ingestion_jobs(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_ingestion_jobs_reply ON ingestion_jobs(reply_channel_id, reply_message_id);
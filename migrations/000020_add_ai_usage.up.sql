@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS ai_usage (
+    id SERIAL PRIMARY KEY,
+    operation VARCHAR(16) NOT NULL,
+    outcome VARCHAR(16) NOT NULL,
+    game VARCHAR(32),
+    platform VARCHAR(16),
+    guild_id VARCHAR(64),
+    user_id VARCHAR(64),
+    user_name VARCHAR(255),
+    prompt_tokens INT NOT NULL DEFAULT 0,
+    response_tokens INT NOT NULL DEFAULT 0,
+    total_tokens INT NOT NULL DEFAULT 0,
+    latency_ms INT NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_created_at ON ai_usage(created_at);
+CREATE INDEX IF NOT EXISTS idx_ai_usage_guild ON ai_usage(guild_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_ai_usage_user ON ai_usage(platform, user_id, created_at);
@@ -0,0 +1,4 @@
+UPDATE matches SET file_hash = 'manual-' || id WHERE file_hash IS NULL;
+ALTER TABLE matches ALTER COLUMN file_hash SET NOT NULL;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS is_manual;
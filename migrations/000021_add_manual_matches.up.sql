@@ -0,0 +1,4 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS is_manual BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Matches entered by hand have no screenshot, UNIQUE still holds for the rest since NULLs never collide
+ALTER TABLE matches ALTER COLUMN file_hash DROP NOT NULL;
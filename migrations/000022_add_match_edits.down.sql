@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS match_edits;
+
+DROP INDEX IF EXISTS idx_matches_file_hash_live;
+DROP INDEX IF EXISTS idx_matches_signature_live;
+ALTER TABLE matches ADD CONSTRAINT matches_file_hash_key UNIQUE (file_hash);
+ALTER TABLE matches ADD CONSTRAINT matches_match_signature_key UNIQUE (match_signature);
@@ -0,0 +1,17 @@
+-- Deleted matches must not block uploading the same screenshot again: uniqueness only applies to live rows
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_file_hash_key;
+ALTER TABLE matches DROP CONSTRAINT IF EXISTS matches_match_signature_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_file_hash_live ON matches(file_hash) WHERE is_deleted = FALSE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_signature_live ON matches(match_signature) WHERE is_deleted = FALSE;
+
+CREATE TABLE IF NOT EXISTS match_edits (
+    id SERIAL PRIMARY KEY,
+    match_id INT NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    edited_by VARCHAR(64) NOT NULL,
+    changes TEXT[] NOT NULL,
+    previous TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_edits_match ON match_edits(match_id, created_at);
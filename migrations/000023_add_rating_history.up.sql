@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS rating_history (
+    id SERIAL PRIMARY KEY,
+    game VARCHAR(32) NOT NULL,
+    seq INT NOT NULL,
+    match_id INT NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    player_id INT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    rating_before DOUBLE PRECISION NOT NULL,
+    rating_after DOUBLE PRECISION NOT NULL,
+    played_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_history_player ON rating_history(game, player_id, seq);
+CREATE INDEX IF NOT EXISTS idx_rating_history_match ON rating_history(match_id);
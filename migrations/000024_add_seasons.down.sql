@@ -0,0 +1,9 @@
+INSERT INTO bot_settings (key, value)
+SELECT 'season_start_date', to_char(started_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+FROM seasons
+ORDER BY status = 'active' DESC, started_at DESC
+LIMIT 1
+ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value;
+
+DROP TABLE IF EXISTS season_standings;
+DROP TABLE IF EXISTS seasons;
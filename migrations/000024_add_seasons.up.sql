@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS seasons (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    started_by VARCHAR(64),
+    ended_by VARCHAR(64),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seasons_name ON seasons(LOWER(name));
+-- At most one season is running at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_seasons_active ON seasons(status) WHERE status = 'active';
+
+-- Final standings frozen when a season closes, later edits of its matches do not change them
+CREATE TABLE IF NOT EXISTS season_standings (
+    season_id INT NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    game VARCHAR(32) NOT NULL,
+    player_id INT NOT NULL,
+    player_name VARCHAR(255) NOT NULL,
+    rank INT NOT NULL,
+    matches INT NOT NULL,
+    wins INT NOT NULL,
+    losses INT NOT NULL,
+    kills INT NOT NULL,
+    deaths INT NOT NULL,
+    assists INT NOT NULL,
+    kda DOUBLE PRECISION NOT NULL,
+    rating DOUBLE PRECISION NOT NULL,
+    gold INT NOT NULL DEFAULT 0,
+    hero_damage INT NOT NULL DEFAULT 0,
+    damage_taken INT NOT NULL DEFAULT 0,
+    turret_damage INT NOT NULL DEFAULT 0,
+    teamfight_pct DOUBLE PRECISION NOT NULL DEFAULT 0,
+    mvps INT NOT NULL DEFAULT 0,
+    heroes JSONB,
+    PRIMARY KEY (season_id, game, player_id)
+);
+
+-- The running season starts where the old single season start setting pointed
+INSERT INTO seasons (name, status, started_at)
+SELECT 'Сезон 1', 'active', COALESCE(
+    (SELECT value::timestamptz FROM bot_settings WHERE key = 'season_start_date'),
+    '2025-01-01T00:00:00Z'::timestamptz
+);
+
+DELETE FROM bot_settings WHERE key = 'season_start_date';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS player_hero_stats;
+DROP TABLE IF EXISTS player_stats;
@@ -0,0 +1,27 @@
+-- Current season standings, refreshed for the affected players whenever a counted match changes
+CREATE TABLE IF NOT EXISTS player_stats (
+    game VARCHAR(32) NOT NULL,
+    player_id INT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    matches INT NOT NULL,
+    wins INT NOT NULL,
+    losses INT NOT NULL,
+    kills INT NOT NULL,
+    deaths INT NOT NULL,
+    assists INT NOT NULL,
+    gold BIGINT NOT NULL DEFAULT 0,
+    hero_damage BIGINT NOT NULL DEFAULT 0,
+    damage_taken BIGINT NOT NULL DEFAULT 0,
+    turret_damage BIGINT NOT NULL DEFAULT 0,
+    teamfight_pct DOUBLE PRECISION NOT NULL DEFAULT 0,
+    mvps INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (game, player_id)
+);
+
+CREATE TABLE IF NOT EXISTS player_hero_stats (
+    game VARCHAR(32) NOT NULL,
+    player_id INT NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+    champion VARCHAR(255) NOT NULL,
+    matches INT NOT NULL,
+    PRIMARY KEY (game, player_id, champion)
+);
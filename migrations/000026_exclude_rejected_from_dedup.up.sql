@@ -0,0 +1,5 @@
+-- Rejected matches must not block uploading the same screenshot again either
+DROP INDEX IF EXISTS idx_matches_file_hash_live;
+DROP INDEX IF EXISTS idx_matches_signature_live;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_file_hash_live ON matches(file_hash) WHERE is_deleted = FALSE AND status <> 'rejected';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_matches_signature_live ON matches(match_signature) WHERE is_deleted = FALSE AND status <> 'rejected';
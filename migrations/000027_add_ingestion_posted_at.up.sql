@@ -0,0 +1,2 @@
+-- When the screenshot was posted, dates matches whose scoreboard shows no end time
+ALTER TABLE ingestion_jobs ADD COLUMN IF NOT EXISTS posted_at TIMESTAMPTZ;
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"time"
 	"valhalla/internal/repository"
 
 	"github.com/caarlos0/env/v11"
@@ -15,12 +16,48 @@ type Config struct {
 	GeminiKey     string            `env:"GEMINI_KEY" envDefault:""`
 	LogLevel      string            `env:"LOGGER_LEVEL" envDefault:"debug"`
 
+	// DefaultGame is the game profile of servers and channels without a /game selection: mlbb, hok or dota2
+	DefaultGame string `env:"DEFAULT_GAME" envDefault:"mlbb"`
+
+	// AIProvider selects the scoreboard reader: gemini, fixture (canned responses) or ocr (local tesseract)
+	AIProvider    string `env:"AI_PROVIDER" envDefault:"gemini"`
+	FixturesDir   string `env:"FIXTURES_DIR" envDefault:"testdata/fixtures"`
+	TesseractPath string `env:"TESSERACT_PATH" envDefault:"tesseract"`
+	OCRLang       string `env:"OCR_LANG" envDefault:"eng"`
+
+	// AI budget: scoreboard provider calls and tokens allowed globally, per server and per user, 0 disables a limit
+	AIDailyCalls        int `env:"AI_DAILY_CALLS" envDefault:"0"`
+	AIMonthlyCalls      int `env:"AI_MONTHLY_CALLS" envDefault:"0"`
+	AIDailyTokens       int `env:"AI_DAILY_TOKENS" envDefault:"0"`
+	AIMonthlyTokens     int `env:"AI_MONTHLY_TOKENS" envDefault:"0"`
+	AIGuildDailyCalls   int `env:"AI_GUILD_DAILY_CALLS" envDefault:"0"`
+	AIGuildMonthlyCalls int `env:"AI_GUILD_MONTHLY_CALLS" envDefault:"0"`
+	AIUserDailyCalls    int `env:"AI_USER_DAILY_CALLS" envDefault:"0"`
+	AIUserMonthlyCalls  int `env:"AI_USER_MONTHLY_CALLS" envDefault:"0"`
+
+	// Screenshot ingestion queue: parallel workers, attempts per screenshot and the first retry delay (doubled each time)
+	IngestionWorkers     int           `env:"INGESTION_WORKERS" envDefault:"3"`
+	IngestionMaxAttempts int           `env:"INGESTION_MAX_ATTEMPTS" envDefault:"5"`
+	IngestionRetryDelay  time.Duration `env:"INGESTION_RETRY_DELAY" envDefault:"30s"`
+
+	// AutoApproveConfidence is the per-field confidence above which parsed matches skip review, 0 disables it
+	AutoApproveConfidence float64 `env:"AUTO_APPROVE_CONFIDENCE" envDefault:"0.9"`
+
+	// Leaderboard defaults: the order of the Google sheet (rating, winrate, kda, kills, deaths, assists, matches)
+	// and the matches a player needs to be listed on /top, in the sheet and in the export unless min_games is given.
+	// The sheet has a tab per game, the default game is written to the first one. It is the unfiltered view,
+	// only the sort and the minimum games apply to it
+	LeaderboardSheetSort string `env:"LEADERBOARD_SHEET_SORT" envDefault:"matches"`
+	LeaderboardMinGames  int    `env:"LEADERBOARD_MIN_GAMES" envDefault:"3"`
+
 	AllowedChannelID string   `env:"ALLOWED_CHANNEL_ID" envDefault:""`
 	AdminUserIDs     []string `env:"ADMIN_USER_IDS" envSeparator:"," envDefault:""`
 
 	TelegramAdminIDs []int64 `env:"TELEGRAM_ADMIN_IDS" envSeparator:"," envDefault:""`
 
 	GoogleOwnerEmail string `env:"GOOGLE_OWNER_EMAIL" envDefault:""`
+
+	StorageDir string `env:"STORAGE_DIR" envDefault:"data/screenshots"`
 }
 
 func ReadEnvConfig(cfg *Config) error {
@@ -15,6 +15,7 @@ type Client interface {
 	MakePublic(spreadsheetID string) error
 	ClearRange(spreadsheetID, rangeStr string) error
 	UpdateValues(spreadsheetID, rangeStr string, values [][]interface{}) error
+	EnsureSheet(spreadsheetID, title string) error
 }
 
 type GoogleSheetsClient struct {
@@ -92,3 +93,26 @@ func (c *GoogleSheetsClient) UpdateValues(spreadsheetID, rangeStr string, values
 	}
 	return nil
 }
+
+// EnsureSheet adds a tab with the given title unless the spreadsheet already has one
+func (c *GoogleSheetsClient) EnsureSheet(spreadsheetID, title string) error {
+	resp, err := c.sheets.Spreadsheets.Get(spreadsheetID).Fields("sheets.properties.title").Do()
+	if err != nil {
+		return fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+	for _, sh := range resp.Sheets {
+		if sh.Properties != nil && sh.Properties.Title == title {
+			return nil
+		}
+	}
+
+	_, err = c.sheets.Spreadsheets.BatchUpdate(spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: title}},
+		}},
+	}).Do()
+	if err != nil {
+		return fmt.Errorf("failed to add sheet %q: %w", title, err)
+	}
+	return nil
+}